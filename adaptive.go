@@ -0,0 +1,171 @@
+package fnplot
+
+import (
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// An AdaptiveConfig controls how NewAdaptiveFn samples a function.
+type AdaptiveConfig struct {
+	// Initial is the number of evenly spaced inputs sampled by the initial
+	// pass. If zero, 20 inputs are sampled.
+	Initial int
+	// Extra is the number of inputs sampled after the initial pass where the
+	// output changes the most. If zero, Initial more inputs are sampled.
+	Extra int
+	// Rounds is the number of rounds that the extra inputs are sampled in.
+	// Each round concentrates its inputs based on the samples of the earlier
+	// rounds. If zero, 4 rounds are used.
+	Rounds int
+}
+
+var float64Type = reflect.TypeOf(float64(0))
+
+// NewAdaptiveFn creates a Fn of a function of a single float64 that samples
+// evenly spaced inputs from min to max, then samples extra inputs in the
+// regions where the output changes rapidly or varies the most (see Refine).
+// The plots of functions with steps or phase transitions are smoother than
+// the plots of the same number of uniformly sampled inputs. Running the Fn
+// again samples inputs uniformly from min to max. If fn isn't a function of a
+// single float64 or the samples can't be refined, running the Fn returns the
+// error.
+func NewAdaptiveFn(fn interface{}, min, max float64, config AdaptiveConfig) Fn {
+	f := newFn(fn, NewValuesSet(NewMemoryStore(0)), Float64Range(min, max))
+	if err := checkAdaptive(f.fn); err != nil {
+		f.p = errorProp(err)
+		return f
+	}
+	initial := config.Initial
+	if initial <= 0 {
+		initial = 20
+	}
+	extra := config.Extra
+	if extra <= 0 {
+		extra = initial
+	}
+	rounds := config.Rounds
+	if rounds <= 0 {
+		rounds = 4
+	}
+
+	next := Linspace(min, max, initial)
+	for i := 0; i < initial; i++ {
+		x := reflect.ValueOf(next(nil).Result)
+		if failure := f.set.sample(f.call, f.fn, []reflect.Value{x}); failure != nil {
+			panic(failure.Panic)
+		}
+	}
+	for i := 0; i < rounds; i++ {
+		n := extra / rounds
+		if i < extra%rounds {
+			n++
+		}
+		if err := f.Refine(n); err != nil {
+			f.p = errorProp(errors.WithMessage(err, "error refining samples"))
+			return f
+		}
+	}
+	return f
+}
+
+// checkAdaptive returns an error if fnVal isn't a function of a single
+// float64.
+func checkAdaptive(fnVal reflect.Value) error {
+	if !fnVal.IsValid() {
+		return errors.New("Fn has no function to run")
+	}
+	if fnVal.Kind() != reflect.Func || fnVal.Type().NumIn() != 1 || fnVal.Type().In(0) != float64Type {
+		return errors.Errorf("adaptive sampling needs a function of a single float64, got %s", fnVal.Type())
+	}
+	return nil
+}
+
+// Refine samples a function of a single float64 samples more times between
+// the inputs already sampled, concentrating the inputs where the output
+// changes the most between neighboring inputs. Each gap between neighboring
+// inputs is scored by how much the output changes across it, relative to the
+// range of the outputs, plus its width, relative to the range of the inputs,
+// so that wide gaps are still filled in. The inputs are spread evenly within
+// each gap in proportion to its score.
+func (fn Fn) Refine(samples int) error {
+	if err := checkAdaptive(fn.fn); err != nil {
+		return err
+	}
+	inputs, outputs, err := fn.set.scalars()
+	if err != nil {
+		return errors.WithMessage(err, "error converting values")
+	}
+	var points []xy
+	for i := range inputs {
+		x, _ := inputs[i].Float64()
+		y, _ := outputs[i].Float64()
+		if !math.IsNaN(x) && !math.IsInf(x, 0) && !math.IsNaN(y) && !math.IsInf(y, 0) {
+			points = append(points, xy{x: x, y: y})
+		}
+	}
+	if len(points) < 2 {
+		return errors.New("refining needs at least two samples")
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].x < points[j].x })
+
+	minY, maxY := points[0].y, points[0].y
+	for _, p := range points {
+		minY, maxY = math.Min(minY, p.y), math.Max(maxY, p.y)
+	}
+	xRange, yRange := points[len(points)-1].x-points[0].x, maxY-minY
+	var gaps []gap
+	var total float64
+	for i := 1; i < len(points); i++ {
+		dx := points[i].x - points[i-1].x
+		if dx == 0 {
+			continue
+		}
+		score := dx / xRange
+		if yRange > 0 {
+			score += math.Abs(points[i].y-points[i-1].y) / yRange
+		}
+		gaps = append(gaps, gap{lo: points[i-1].x, hi: points[i].x, score: score})
+		total += score
+	}
+	if len(gaps) == 0 {
+		return errors.New("refining needs at least two different inputs")
+	}
+
+	// Each gap gets its share of the samples rounded down, and the remaining
+	// samples go to the gaps with the highest scores.
+	assigned := 0
+	for i := range gaps {
+		gaps[i].n = int(float64(samples) * gaps[i].score / total)
+		assigned += gaps[i].n
+	}
+	sort.SliceStable(gaps, func(i, j int) bool { return gaps[i].score > gaps[j].score })
+	for i := 0; assigned < samples; i = (i + 1) % len(gaps) {
+		gaps[i].n++
+		assigned++
+	}
+	for _, g := range gaps {
+		for j := 1; j <= g.n; j++ {
+			x := g.lo + (g.hi-g.lo)*float64(j)/float64(g.n+1)
+			if failure := fn.set.sample(fn.call, fn.fn, []reflect.Value{reflect.ValueOf(x)}); failure != nil {
+				panic(failure.Panic)
+			}
+		}
+	}
+	return nil
+}
+
+// An xy is a point of a sampled function.
+type xy struct {
+	x, y float64
+}
+
+// A gap is the range between neighboring sampled inputs, scored by how much
+// it needs more samples.
+type gap struct {
+	lo, hi float64
+	score  float64
+	n      int
+}
@@ -0,0 +1,45 @@
+package fnplot
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAdaptiveFn(t *testing.T) {
+	step := func(x float64) float64 {
+		if x < 0.55 {
+			return 0
+		}
+		return 1
+	}
+	fn := NewAdaptiveFn(step, 0, 1, AdaptiveConfig{Initial: 11, Extra: 40})
+	inputs, _ := setFloats(t, fn.ValuesSet())
+	require.Len(t, inputs, 51, "Expected the initial and extra samples")
+
+	var nearStep int
+	for _, x := range inputs {
+		if x > 0.5 && x < 0.6 {
+			nearStep++
+		}
+	}
+	assert.True(t, nearStep > 20, "Expected most extra samples near the step, got %d", nearStep)
+
+	fn = NewAdaptiveFn(func(i int) int { return i }, 0, 1, AdaptiveConfig{})
+	assert.Equal(t, 0, fn.ValuesSet().Len(), "Expected no samples of a function of an int")
+	assert.Error(t, fn.Refine(10), "Expected an error refining a function of an int")
+
+	fn = NewAdaptiveFn(step, 0, 1, AdaptiveConfig{Initial: 1})
+	assert.Equal(t, 1, fn.ValuesSet().Len(), "Expected only the initial sample")
+	assert.Error(t, fn.RunWithConfig(10, RunConfig{}), "Expected the error refining a single sample")
+}
+
+func TestRefine(t *testing.T) {
+	fn := newFn(math.Sqrt, NewValuesSet(NewMemoryStore(0)), Float64Range(0, 1))
+	assert.Error(t, fn.Refine(10), "Expected an error refining without samples")
+	require.NoError(t, fn.Replay(newTestSet(t, []float64{0, 1}, []float64{0, 1})), "Error sampling inputs")
+	require.NoError(t, fn.Refine(10), "Error refining samples")
+	assert.Equal(t, 12, fn.ValuesSet().Len(), "Expected the extra samples")
+}
@@ -0,0 +1,79 @@
+package fnplot
+
+import (
+	"image"
+	"image/color/palette"
+	imagedraw "image/draw"
+	"image/gif"
+	"os"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/plot/vg/draw"
+	"gonum.org/v1/plot/vg/vgimg"
+)
+
+const (
+	// gifFrameDelay is the delay between the frames of an animated plot, in
+	// 100ths of a second.
+	gifFrameDelay = 25
+	// gifFinalDelay is the delay of the last frame of an animated plot before
+	// the animation loops, in 100ths of a second.
+	gifFinalDelay = 200
+)
+
+// frame renders the plot as a paletted image for an animated GIF.
+func (pl Plot) frame() (*image.Paletted, error) {
+	p, err := pl.render()
+	if err != nil {
+		return nil, err
+	}
+	c := vgimg.New(pl.size())
+	p.Draw(draw.New(c))
+
+	img := c.Image()
+	frame := image.NewPaletted(img.Bounds(), palette.Plan9)
+	imagedraw.Draw(frame, frame.Rect, img, img.Bounds().Min, imagedraw.Src)
+	return frame, nil
+}
+
+// SaveGIF samples the functions of the plot and writes an animated GIF to the
+// given filename that shows the plot after every batch of samples. It shows
+// how the shape of the plot stabilizes as samples are collected. Each function
+// of the plot is run samples times in batches of every samples, in addition to
+// the samples run when it was created.
+func (pl Plot) SaveGIF(filename string, samples, every int) error {
+	if samples <= 0 {
+		return errors.New("the number of samples must be greater than zero")
+	}
+	if every <= 0 {
+		return errors.New("the number of samples per frame must be greater than zero")
+	}
+
+	anim := &gif.GIF{}
+	err := runBatches(pl.series(), samples, every, func(int) error {
+		frame, err := pl.frame()
+		if err != nil {
+			return errors.WithMessage(err, "error rendering animation frame")
+		}
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, gifFrameDelay)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(anim.Delay) == 0 {
+		return errors.New("no animation frames were rendered")
+	}
+	anim.Delay[len(anim.Delay)-1] = gifFinalDelay
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return errors.WithMessage(err, "error creating GIF file")
+	}
+	if err := gif.EncodeAll(f, anim); err != nil {
+		f.Close()
+		return errors.WithMessage(err, "error writing GIF")
+	}
+	return errors.WithMessage(f.Close(), "error closing GIF file")
+}
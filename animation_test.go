@@ -0,0 +1,58 @@
+package fnplot
+
+import (
+	"image/gif"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gonum.org/v1/plot/vg"
+)
+
+func TestSaveGIF(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fnplot")
+	require.NoError(t, err, "Error creating temporary directory")
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		description string
+		samples     int
+		every       int
+		frames      int
+		err         bool
+	}{
+		{description: "even batches", samples: 30, every: 10, frames: 3},
+		{description: "partial last batch", samples: 30, every: 20, frames: 2},
+		{description: "single batch", samples: 10, every: 20, frames: 1},
+		{description: "zero every", samples: 10, every: 0, err: true},
+		{description: "negative every", samples: 10, every: -1, err: true},
+		{description: "zero samples", samples: 0, every: 10, err: true},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			fn := NewFn(math.Sqrt, 10, Float64Range(0, 100))
+			pl := Plot{Fn: fn, X: &StdAxix{}, Y: &StdAxix{}, Width: 2 * vg.Inch, Height: 1 * vg.Inch}
+			filename := filepath.Join(dir, test.description+".gif")
+			err := pl.SaveGIF(filename, test.samples, test.every)
+			if test.err {
+				assert.Error(t, err, "Expected an error saving the GIF")
+				return
+			}
+			require.NoError(t, err, "Error saving the GIF")
+			assert.Equal(t, 10+test.samples, fn.ValuesSet().Len(), "Expected the function to be sampled")
+
+			f, err := os.Open(filename)
+			require.NoError(t, err, "Error opening the GIF")
+			defer f.Close()
+			anim, err := gif.DecodeAll(f)
+			require.NoError(t, err, "Error decoding the GIF")
+			assert.Len(t, anim.Image, test.frames, "Expected a frame for each batch of samples")
+			assert.Equal(t, gifFinalDelay, anim.Delay[len(anim.Delay)-1], "Expected the final delay on the last frame")
+		})
+	}
+}
@@ -1,14 +1,27 @@
 package fnplot
 
 import (
+	"math"
 	"math/big"
+	"sort"
+	"time"
 
 	"github.com/ALTree/bigfloat"
+	"gonum.org/v1/plot"
 )
 
+// An Axis converts scalar values to points on a plot axis. Before any points
+// are requested, SetValues is called with every scalar value that will be
+// plotted on the axis, then SetMinValue and SetMaxValue are called with the
+// smallest and largest of them.
+//
+// An Axis that also implements plot.Ticker is used to place and label the tick
+// marks on the plot axis.
 type Axis interface {
 	Point(*big.Float) float64
+	SetMinValue(*big.Float)
 	SetMaxValue(*big.Float)
+	SetValues([]*big.Float)
 }
 
 type StdAxix struct{}
@@ -20,20 +33,28 @@ func (StdAxix) Point(p *big.Float) float64 {
 
 func (*StdAxix) SetMaxValue(*big.Float) {}
 
+func (*StdAxix) SetMinValue(*big.Float) {}
+
+func (*StdAxix) SetValues([]*big.Float) {}
+
 type ScaledAxis struct {
 	Max   float64
 	ratio *big.Float
 }
 
 func (sa ScaledAxis) Point(p *big.Float) float64 {
-	scaled, _ := big.NewFloat(0).Mul(p, sa.ratio).Float64()
+	scaled, _ := newFloat().Mul(p, sa.ratio).Float64()
 	return scaled
 }
 
 func (sa *ScaledAxis) SetMaxValue(v *big.Float) {
-	sa.ratio = big.NewFloat(0).Quo(big.NewFloat(sa.Max), v)
+	sa.ratio = newFloat().Quo(big.NewFloat(sa.Max), v)
 }
 
+func (*ScaledAxis) SetMinValue(*big.Float) {}
+
+func (*ScaledAxis) SetValues([]*big.Float) {}
+
 type LnAxis struct{}
 
 func (la LnAxis) Point(p *big.Float) float64 {
@@ -46,6 +67,10 @@ func (la LnAxis) Point(p *big.Float) float64 {
 
 func (*LnAxis) SetMaxValue(*big.Float) {}
 
+func (*LnAxis) SetMinValue(*big.Float) {}
+
+func (*LnAxis) SetValues([]*big.Float) {}
+
 type LnScaledAxis struct {
 	Max   float64
 	ratio *big.Float
@@ -55,10 +80,421 @@ func (lsa LnScaledAxis) Point(p *big.Float) float64 {
 	if p.Cmp(big.NewFloat(0)) == 0 {
 		return 0
 	}
-	scaled, _ := big.NewFloat(0).Mul(bigfloat.Log(p), lsa.ratio).Float64()
+	scaled, _ := newFloat().Mul(bigfloat.Log(p), lsa.ratio).Float64()
 	return scaled
 }
 
 func (lsa *LnScaledAxis) SetMaxValue(v *big.Float) {
-	lsa.ratio = big.NewFloat(0).Quo(big.NewFloat(lsa.Max), bigfloat.Log(v))
+	lsa.ratio = newFloat().Quo(big.NewFloat(lsa.Max), bigfloat.Log(v))
+}
+
+func (*LnScaledAxis) SetMinValue(*big.Float) {}
+
+func (*LnScaledAxis) SetValues([]*big.Float) {}
+
+// RankAxis plots each value at its percentile rank (between 0 and 100) among all
+// values on the axis rather than at its magnitude. It is useful for values that
+// are distributed very non-uniformly, like hash outputs. Equal values share the
+// same rank.
+type RankAxis struct {
+	sorted []*big.Float
+}
+
+func (ra RankAxis) Point(p *big.Float) float64 {
+	n := len(ra.sorted)
+	if n == 0 {
+		return 0
+	}
+	below := sort.Search(n, func(i int) bool { return ra.sorted[i].Cmp(p) >= 0 })
+	notAbove := sort.Search(n, func(i int) bool { return ra.sorted[i].Cmp(p) > 0 })
+	return 100 * (float64(below) + float64(notAbove-below)/2) / float64(n)
+}
+
+func (*RankAxis) SetMinValue(*big.Float) {}
+
+func (*RankAxis) SetMaxValue(*big.Float) {}
+
+func (ra *RankAxis) SetValues(values []*big.Float) {
+	ra.sorted = sortedFloats(values)
+}
+
+// RangeAxis scales values linearly so that the smallest value on the axis is
+// plotted at 0 and the largest value is plotted at Max. Unlike ScaledAxis, it
+// doesn't assume the values start at zero, so values clustered in a narrow
+// range far from zero are spread across the whole axis.
+type RangeAxis struct {
+	Max float64
+	min *big.Float
+	max *big.Float
+}
+
+func (ra RangeAxis) Point(p *big.Float) float64 {
+	if ra.min == nil || ra.max == nil {
+		return 0
+	}
+	span := newFloat().Sub(ra.max, ra.min)
+	if span.Sign() == 0 {
+		return 0
+	}
+	offset := newFloat().Sub(p, ra.min)
+	scaled, _ := offset.Mul(offset, big.NewFloat(ra.Max)).Quo(offset, span).Float64()
+	return scaled
+}
+
+func (ra *RangeAxis) SetMinValue(v *big.Float) {
+	ra.min = v
+}
+
+func (ra *RangeAxis) SetMaxValue(v *big.Float) {
+	ra.max = v
+}
+
+func (*RangeAxis) SetValues([]*big.Float) {}
+
+// NormalizedAxis maps the smallest value on the axis to 0 and the largest
+// value to 1 so that the shapes of plots can be compared regardless of the
+// magnitude of their values.
+type NormalizedAxis struct {
+	rangeAxis RangeAxis
+}
+
+func (na NormalizedAxis) Point(p *big.Float) float64 {
+	return na.rangeAxis.Point(p)
+}
+
+func (na *NormalizedAxis) SetMinValue(v *big.Float) {
+	na.rangeAxis.SetMinValue(v)
+}
+
+func (na *NormalizedAxis) SetMaxValue(v *big.Float) {
+	na.rangeAxis.Max = 1
+	na.rangeAxis.SetMaxValue(v)
+}
+
+func (*NormalizedAxis) SetValues([]*big.Float) {}
+
+// TimeAxis plots scalar values as timestamps in Unix nanoseconds and labels the
+// tick marks with formatted dates.
+type TimeAxis struct {
+	// Format is the time layout used for tick labels. If empty, time.RFC3339 is
+	// used.
+	Format string
+	// Location is the time zone used for tick labels. If nil, UTC is used.
+	Location *time.Location
+}
+
+func (TimeAxis) Point(p *big.Float) float64 {
+	fp, _ := p.Float64()
+	return fp
+}
+
+func (*TimeAxis) SetMinValue(*big.Float) {}
+
+func (*TimeAxis) SetMaxValue(*big.Float) {}
+
+func (*TimeAxis) SetValues([]*big.Float) {}
+
+func (ta TimeAxis) Ticks(min, max float64) []plot.Tick {
+	loc := ta.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	return plot.TimeTicks{
+		Format: ta.Format,
+		Time: func(t float64) time.Time {
+			return time.Unix(0, int64(t)).In(loc)
+		},
+	}.Ticks(min, max)
+}
+
+// DurationAxis plots scalar values as durations in nanoseconds and labels the
+// tick marks with human-readable durations (e.g. "250ms", "1.5s", "2m0s").
+type DurationAxis struct{}
+
+func (DurationAxis) Point(p *big.Float) float64 {
+	fp, _ := p.Float64()
+	return fp
+}
+
+func (*DurationAxis) SetMinValue(*big.Float) {}
+
+func (*DurationAxis) SetMaxValue(*big.Float) {}
+
+func (*DurationAxis) SetValues([]*big.Float) {}
+
+func (DurationAxis) Ticks(min, max float64) []plot.Tick {
+	ticks := plot.DefaultTicks{}.Ticks(min, max)
+	for i := range ticks {
+		if ticks[i].Label == "" {
+			continue
+		}
+		ticks[i].Label = time.Duration(ticks[i].Value).String()
+	}
+	return ticks
+}
+
+// CategoricalAxis plots each distinct value in its own evenly spaced slot (0,
+// 1, 2, ...) and labels the tick marks with the category names. It is useful
+// for functions of a small set of discrete values, like strings or enums.
+type CategoricalAxis struct {
+	// Categories are the names of the expected string values, in the order
+	// they should appear on the axis. Values that aren't one of the Categories
+	// are given slots after them in ascending order and labeled with their
+	// scalar value.
+	Categories []string
+	slots      map[string]int
+	labels     []string
+}
+
+func (ca CategoricalAxis) Point(p *big.Float) float64 {
+	slot, ok := ca.slots[p.Text('g', -1)]
+	if !ok {
+		return -1
+	}
+	return float64(slot)
+}
+
+func (*CategoricalAxis) SetMinValue(*big.Float) {}
+
+func (*CategoricalAxis) SetMaxValue(*big.Float) {}
+
+func (ca *CategoricalAxis) SetValues(values []*big.Float) {
+	ca.slots = make(map[string]int)
+	ca.labels = nil
+	addSlot := func(key, label string) {
+		if _, ok := ca.slots[key]; ok {
+			return
+		}
+		ca.slots[key] = len(ca.labels)
+		ca.labels = append(ca.labels, label)
+	}
+
+	for _, category := range ca.Categories {
+		// Scalar never returns an error for a string value.
+		s, _ := NewValues(category).Scalar()
+		addSlot(s.Text('g', -1), category)
+	}
+
+	for _, v := range sortedFloats(values) {
+		addSlot(v.Text('g', -1), v.Text('g', 10))
+	}
+}
+
+func (ca CategoricalAxis) Ticks(min, max float64) []plot.Tick {
+	var ticks []plot.Tick
+	for slot, label := range ca.labels {
+		if float64(slot) < min || float64(slot) > max {
+			continue
+		}
+		ticks = append(ticks, plot.Tick{Value: float64(slot), Label: label})
+	}
+	return ticks
+}
+
+// autoAxisLogDecades is the number of orders of magnitude that non-negative
+// values must span before AutoAxis chooses a log scale.
+const autoAxisLogDecades = 6
+
+// AutoAxis inspects the values on the axis and chooses an appropriate axis to
+// plot them:
+//
+//   - A natural log scale (LnAxis) if the values are non-negative and span more
+//     than 6 orders of magnitude.
+//   - A scaled axis (ScaledAxis, or RangeAxis if there are negative values) if
+//     the values are too large to be represented as a float64.
+//   - A linear axis (StdAxix) otherwise.
+//
+// Use Chosen to find out which axis was chosen.
+type AutoAxis struct {
+	// Max is the largest point plotted if a scaled axis is chosen. If zero,
+	// 1000 is used.
+	Max    float64
+	values []*big.Float
+	min    *big.Float
+	chosen Axis
+}
+
+func (aa AutoAxis) Point(p *big.Float) float64 {
+	if aa.chosen == nil {
+		return 0
+	}
+	return aa.chosen.Point(p)
+}
+
+func (aa *AutoAxis) SetMinValue(v *big.Float) {
+	aa.min = v
+}
+
+// SetMaxValue chooses the axis based on the previously set values and minimum
+// value.
+func (aa *AutoAxis) SetMaxValue(v *big.Float) {
+	aa.chosen = aa.choose(v)
+	aa.chosen.SetValues(aa.values)
+	aa.chosen.SetMinValue(aa.min)
+	aa.chosen.SetMaxValue(v)
+}
+
+func (aa *AutoAxis) SetValues(values []*big.Float) {
+	aa.values = values
+}
+
+func (aa AutoAxis) choose(max *big.Float) Axis {
+	if max == nil || aa.min == nil {
+		return &StdAxix{}
+	}
+
+	if aa.min.Sign() >= 0 {
+		var minPositive *big.Float
+		for _, v := range aa.values {
+			if v.Sign() > 0 && (minPositive == nil || v.Cmp(minPositive) == -1) {
+				minPositive = v
+			}
+		}
+		if minPositive != nil {
+			decades := float64(max.MantExp(nil)-minPositive.MantExp(nil)) * math.Log10(2)
+			if decades > autoAxisLogDecades {
+				return &LnAxis{}
+			}
+		}
+	}
+
+	scaledMax := aa.Max
+	if scaledMax == 0 {
+		scaledMax = 1000
+	}
+	if f, _ := max.Float64(); math.IsInf(f, 0) {
+		if aa.min.Sign() < 0 {
+			return &RangeAxis{Max: scaledMax}
+		}
+		return &ScaledAxis{Max: scaledMax}
+	}
+	if f, _ := aa.min.Float64(); math.IsInf(f, 0) {
+		return &RangeAxis{Max: scaledMax}
+	}
+	return &StdAxix{}
+}
+
+// Chosen returns the axis chosen to plot the values, or nil if no values have
+// been set yet.
+func (aa AutoAxis) Chosen() Axis {
+	return aa.chosen
+}
+
+func (aa AutoAxis) Ticks(min, max float64) []plot.Tick {
+	if ticker, ok := aa.chosen.(plot.Ticker); ok {
+		return ticker.Ticks(min, max)
+	}
+	return plot.DefaultTicks{}.Ticks(min, max)
+}
+
+// sortedFloats returns a sorted copy of the given values.
+func sortedFloats(values []*big.Float) []*big.Float {
+	sorted := make([]*big.Float, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) == -1 })
+	return sorted
+}
+
+// nearestRank returns the p-th percentile (between 0 and 100) of the given
+// sorted values using the nearest-rank method.
+func nearestRank(sorted []*big.Float, p float64) *big.Float {
+	if len(sorted) == 0 {
+		return nil
+	}
+	return sorted[rankIndex(len(sorted), p)]
+}
+
+// rankIndex returns the index of the p-th percentile (between 0 and 100) of n
+// sorted values using the nearest-rank method. n must be more than zero.
+func rankIndex(n int, p float64) int {
+	i := int(math.Ceil(p/100*float64(n))) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= n {
+		i = n - 1
+	}
+	return i
+}
+
+// ClampAxis clips outlier values to configurable bounds before plotting them on
+// another axis so that a few extreme values don't squash the rest of the plot.
+type ClampAxis struct {
+	// Axis plots the clamped values. If nil, a StdAxix is used.
+	Axis Axis
+	// Min and Max are absolute bounds for the values. A nil bound is ignored.
+	Min, Max *big.Float
+	// Percentile, if greater than zero, clamps values above the Percentile-th
+	// percentile and below the (100-Percentile)-th percentile of the values on
+	// the axis. For example, a Percentile of 99 clips the top and bottom 1% of
+	// values.
+	Percentile float64
+	lower      *big.Float
+	upper      *big.Float
+}
+
+func (ca ClampAxis) clamp(p *big.Float) *big.Float {
+	if ca.lower != nil && p.Cmp(ca.lower) == -1 {
+		return ca.lower
+	}
+	if ca.upper != nil && p.Cmp(ca.upper) == 1 {
+		return ca.upper
+	}
+	return p
+}
+
+func (ca ClampAxis) Point(p *big.Float) float64 {
+	return ca.axis().Point(ca.clamp(p))
+}
+
+func (ca *ClampAxis) SetMinValue(v *big.Float) {
+	ca.axis().SetMinValue(ca.clamp(v))
+}
+
+func (ca *ClampAxis) SetMaxValue(v *big.Float) {
+	ca.axis().SetMaxValue(ca.clamp(v))
+}
+
+// SetValues calculates the clamp bounds from the given values and sets the
+// clamped values on the underlying axis.
+func (ca *ClampAxis) SetValues(values []*big.Float) {
+	if ca.Axis == nil {
+		ca.Axis = &StdAxix{}
+	}
+
+	ca.lower, ca.upper = ca.Min, ca.Max
+	if ca.Percentile > 0 && len(values) > 0 {
+		sorted := sortedFloats(values)
+		lower := nearestRank(sorted, 100-ca.Percentile)
+		upper := nearestRank(sorted, ca.Percentile)
+		if lower.Cmp(upper) == 1 {
+			lower, upper = upper, lower
+		}
+		if ca.lower == nil || lower.Cmp(ca.lower) == 1 {
+			ca.lower = lower
+		}
+		if ca.upper == nil || upper.Cmp(ca.upper) == -1 {
+			ca.upper = upper
+		}
+	}
+
+	clamped := make([]*big.Float, len(values))
+	for i := range values {
+		clamped[i] = ca.clamp(values[i])
+	}
+	ca.Axis.SetValues(clamped)
+}
+
+func (ca ClampAxis) axis() Axis {
+	if ca.Axis == nil {
+		return &StdAxix{}
+	}
+	return ca.Axis
+}
+
+func (ca ClampAxis) Ticks(min, max float64) []plot.Tick {
+	if ticker, ok := ca.Axis.(plot.Ticker); ok {
+		return ticker.Ticks(min, max)
+	}
+	return plot.DefaultTicks{}.Ticks(min, max)
 }
@@ -0,0 +1,315 @@
+package fnplot
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bigFloats(xs ...float64) []*big.Float {
+	fs := make([]*big.Float, len(xs))
+	for i := range xs {
+		fs[i] = big.NewFloat(xs[i])
+	}
+	return fs
+}
+
+func TestRankAxis(t *testing.T) {
+	tests := []struct {
+		description string
+		values      []*big.Float
+		point       *big.Float
+		expected    float64
+	}{
+		{
+			description: "No values",
+			values:      nil,
+			point:       big.NewFloat(10),
+			expected:    0,
+		},
+		{
+			description: "Smallest value",
+			values:      bigFloats(1, 1e10, 1e20, 1e30),
+			point:       big.NewFloat(1),
+			expected:    12.5,
+		},
+		{
+			description: "Largest value",
+			values:      bigFloats(1, 1e10, 1e20, 1e30),
+			point:       big.NewFloat(1e30),
+			expected:    87.5,
+		},
+		{
+			description: "Equal values share a rank",
+			values:      bigFloats(5, 1, 5, 5),
+			point:       big.NewFloat(5),
+			expected:    62.5,
+		},
+		{
+			description: "Value not on the axis",
+			values:      bigFloats(1, 2, 3, 4),
+			point:       big.NewFloat(2.5),
+			expected:    50,
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			axis := &RankAxis{}
+			axis.SetValues(test.values)
+			assert.Equal(t, test.expected, axis.Point(test.point), "Expected and actual points are different")
+		})
+	}
+}
+
+func TestRangeAxis(t *testing.T) {
+	tests := []struct {
+		description string
+		min, max    *big.Float
+		point       *big.Float
+		expected    float64
+	}{
+		{
+			description: "Minimum value",
+			min:         big.NewFloat(1e18),
+			max:         big.NewFloat(1.0001e18),
+			point:       big.NewFloat(1e18),
+			expected:    0,
+		},
+		{
+			description: "Maximum value",
+			min:         big.NewFloat(1e18),
+			max:         big.NewFloat(1.0001e18),
+			point:       big.NewFloat(1.0001e18),
+			expected:    100,
+		},
+		{
+			description: "Clustered values are spread out",
+			min:         big.NewFloat(1e18),
+			max:         big.NewFloat(1.0001e18),
+			point:       big.NewFloat(1.00005e18),
+			expected:    50,
+		},
+		{
+			description: "Negative minimum",
+			min:         big.NewFloat(-50),
+			max:         big.NewFloat(50),
+			point:       big.NewFloat(0),
+			expected:    50,
+		},
+		{
+			description: "Empty range",
+			min:         big.NewFloat(7),
+			max:         big.NewFloat(7),
+			point:       big.NewFloat(7),
+			expected:    0,
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			axis := &RangeAxis{Max: 100}
+			axis.SetMinValue(test.min)
+			axis.SetMaxValue(test.max)
+			assert.InDelta(t, test.expected, axis.Point(test.point), 1e-9, "Expected and actual points are different")
+		})
+	}
+}
+
+func TestDurationAxisTicks(t *testing.T) {
+	ticks := DurationAxis{}.Ticks(0, float64(2*time.Second))
+	var labels []string
+	for _, tick := range ticks {
+		if tick.Label != "" {
+			labels = append(labels, tick.Label)
+		}
+	}
+	assert.Equal(t, []string{"0s", "1s", "2s"}, labels, "Expected and actual tick labels are different")
+}
+
+func TestTimeAxis(t *testing.T) {
+	tests := []struct {
+		description string
+		axis        TimeAxis
+		expected    []string
+	}{
+		{
+			description: "default format and location",
+			axis:        TimeAxis{},
+			expected:    []string{"1970-01-01T00:00:00Z", "1970-01-01T00:00:01Z", "1970-01-01T00:00:02Z"},
+		},
+		{
+			description: "format and location",
+			axis:        TimeAxis{Format: "15:04:05", Location: time.FixedZone("UTC+1", 60*60)},
+			expected:    []string{"01:00:00", "01:00:01", "01:00:02"},
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			point := float64(1500 * time.Millisecond)
+			assert.Equal(t, point, test.axis.Point(big.NewFloat(point)), "Expected and actual points are different")
+
+			var labels []string
+			for _, tick := range test.axis.Ticks(0, float64(2*time.Second)) {
+				if tick.Label != "" {
+					labels = append(labels, tick.Label)
+				}
+			}
+			assert.Equal(t, test.expected, labels, "Expected and actual tick labels are different")
+		})
+	}
+}
+
+func TestCategoricalAxis(t *testing.T) {
+	scalar := func(v interface{}) *big.Float {
+		s, err := NewValues(v).Scalar()
+		require.NoError(t, err, "Error calculating scalar value")
+		return s
+	}
+
+	axis := &CategoricalAxis{Categories: []string{"low", "medium", "high"}}
+	axis.SetValues([]*big.Float{
+		scalar("high"),
+		scalar("low"),
+		scalar(uint8(2)),
+		scalar("high"),
+		scalar(uint8(1)),
+	})
+
+	assert.Equal(t, float64(0), axis.Point(scalar("low")), "Expected and actual points are different")
+	assert.Equal(t, float64(1), axis.Point(scalar("medium")), "Expected and actual points are different")
+	assert.Equal(t, float64(2), axis.Point(scalar("high")), "Expected and actual points are different")
+	assert.Equal(t, float64(3), axis.Point(scalar(uint8(1))), "Expected and actual points are different")
+	assert.Equal(t, float64(4), axis.Point(scalar(uint8(2))), "Expected and actual points are different")
+
+	var labels []string
+	for _, tick := range axis.Ticks(0, 4) {
+		labels = append(labels, tick.Label)
+	}
+	assert.Equal(t, []string{"low", "medium", "high", "1", "2"}, labels, "Expected and actual tick labels are different")
+}
+
+func TestAutoAxis(t *testing.T) {
+	huge := big.NewFloat(0).SetMantExp(big.NewFloat(1), 2000)
+	tests := []struct {
+		description string
+		values      []*big.Float
+		expected    Axis
+	}{
+		{
+			description: "Small linear values",
+			values:      bigFloats(1, 50, 100),
+			expected:    &StdAxix{},
+		},
+		{
+			description: "Negative values",
+			values:      bigFloats(-1e9, 0, 1e9),
+			expected:    &StdAxix{},
+		},
+		{
+			description: "Values spanning many orders of magnitude",
+			values:      bigFloats(0, 1, 1e12),
+			expected:    &LnAxis{},
+		},
+		{
+			description: "Values too large for a float64",
+			values:      []*big.Float{big.NewFloat(0).Quo(huge, big.NewFloat(2)), huge},
+			expected:    &ScaledAxis{Max: 1000},
+		},
+		{
+			description: "Negative values too large for a float64",
+			values:      []*big.Float{big.NewFloat(0).Neg(huge), huge},
+			expected:    &RangeAxis{Max: 1000},
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			min, max := test.values[0], test.values[len(test.values)-1]
+			axis := &AutoAxis{}
+			axis.SetValues(test.values)
+			axis.SetMinValue(min)
+			axis.SetMaxValue(max)
+			assert.IsType(t, test.expected, axis.Chosen(), "Expected and actual chosen axes are different")
+			assert.False(t, math.IsInf(axis.Point(max), 0), "Expected the largest value to be a finite point")
+		})
+	}
+}
+
+func TestNormalizedAxis(t *testing.T) {
+	axis := &NormalizedAxis{}
+	axis.SetValues(bigFloats(-20, 0, 60))
+	axis.SetMinValue(big.NewFloat(-20))
+	axis.SetMaxValue(big.NewFloat(60))
+
+	assert.Equal(t, float64(0), axis.Point(big.NewFloat(-20)), "Expected and actual points are different")
+	assert.Equal(t, 0.25, axis.Point(big.NewFloat(0)), "Expected and actual points are different")
+	assert.Equal(t, float64(1), axis.Point(big.NewFloat(60)), "Expected and actual points are different")
+}
+
+func TestClampAxis(t *testing.T) {
+	values := bigFloats(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 1e9)
+	tests := []struct {
+		description string
+		axis        *ClampAxis
+		point       *big.Float
+		expected    float64
+	}{
+		{
+			description: "Absolute upper bound",
+			axis:        &ClampAxis{Max: big.NewFloat(20)},
+			point:       big.NewFloat(1e9),
+			expected:    20,
+		},
+		{
+			description: "Absolute lower bound",
+			axis:        &ClampAxis{Min: big.NewFloat(5)},
+			point:       big.NewFloat(2),
+			expected:    5,
+		},
+		{
+			description: "Percentile upper bound",
+			axis:        &ClampAxis{Percentile: 90},
+			point:       big.NewFloat(1e9),
+			expected:    10,
+		},
+		{
+			description: "Percentile lower bound",
+			axis:        &ClampAxis{Percentile: 90},
+			point:       big.NewFloat(1),
+			expected:    2,
+		},
+		{
+			description: "Tighter of absolute and percentile bounds",
+			axis:        &ClampAxis{Percentile: 90, Max: big.NewFloat(8)},
+			point:       big.NewFloat(1e9),
+			expected:    8,
+		},
+		{
+			description: "Value inside bounds",
+			axis:        &ClampAxis{Percentile: 90},
+			point:       big.NewFloat(6),
+			expected:    6,
+		},
+		{
+			description: "Clamped values on a scaled axis",
+			axis:        &ClampAxis{Axis: &ScaledAxis{Max: 100}, Percentile: 90},
+			point:       big.NewFloat(1e9),
+			expected:    100,
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			test.axis.SetValues(values)
+			test.axis.SetMinValue(values[0])
+			test.axis.SetMaxValue(values[len(values)-1])
+			assert.Equal(t, test.expected, test.axis.Point(test.point), "Expected and actual points are different")
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package fnplot
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// A Benchmark is the measurements of a benchmark for a sweep of input sizes,
+// read from the output of go test -bench.
+type Benchmark struct {
+	// Name is the name of the benchmark without the size and GOMAXPROCS
+	// suffix, like "BenchmarkSort" for "BenchmarkSort/size=100-8".
+	Name string
+	// Metrics are the measurements of the benchmark by unit, like "ns/op",
+	// "B/op", and "allocs/op". The input of each pair is the size, as an int,
+	// and the output is the measurement, as a float64.
+	Metrics map[string]*ValuesSet
+}
+
+var (
+	// benchLine matches the result lines of go test -bench output.
+	benchLine = regexp.MustCompile(`^(Benchmark\S*)\s+(\d+)\s+(.+)$`)
+	// benchProcs matches the GOMAXPROCS suffix of a benchmark name.
+	benchProcs = regexp.MustCompile(`-\d+$`)
+	// benchSize matches the sub-benchmark names that are sizes, like "100" or
+	// "size=100".
+	benchSize = regexp.MustCompile(`^(?:[^=]+=)?(\d+)$`)
+)
+
+// ReadBenchmarks reads the results of go test -bench output, or of a benchfmt
+// file, from r. The size of each result is the last sub-benchmark name that is
+// an integer, optionally named like "size=100". Results without a size are
+// skipped. The benchmarks are returned in the order they first appear.
+func ReadBenchmarks(r io.Reader) ([]Benchmark, error) {
+	var benchmarks []Benchmark
+	indexes := make(map[string]int)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		match := benchLine.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil {
+			continue
+		}
+		name, size, ok := splitBenchSize(match[1])
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(match[3])
+		if len(fields)%2 != 0 {
+			return nil, errors.New("invalid benchmark result: " + scanner.Text())
+		}
+		i, ok := indexes[name]
+		if !ok {
+			i = len(benchmarks)
+			indexes[name] = i
+			benchmarks = append(benchmarks, Benchmark{Name: name, Metrics: make(map[string]*ValuesSet)})
+		}
+		for j := 0; j < len(fields); j += 2 {
+			value, err := strconv.ParseFloat(fields[j], 64)
+			if err != nil {
+				return nil, errors.WithMessage(err, "error parsing benchmark result "+scanner.Text())
+			}
+			unit := fields[j+1]
+			set := benchmarks[i].Metrics[unit]
+			if set == nil {
+				set = NewValuesSet(NewMemoryStore(0))
+				benchmarks[i].Metrics[unit] = set
+			}
+			if err := set.insert(NewValues(size), NewValues(value)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.WithMessage(err, "error reading benchmark results")
+	}
+	return benchmarks, nil
+}
+
+// splitBenchSize returns the name of a benchmark without its size and
+// GOMAXPROCS suffix, and its size.
+func splitBenchSize(fullName string) (name string, size int, ok bool) {
+	parts := strings.Split(benchProcs.ReplaceAllString(fullName, ""), "/")
+	for i := len(parts) - 1; i > 0; i-- {
+		match := benchSize.FindStringSubmatch(parts[i])
+		if match == nil {
+			continue
+		}
+		size, err := strconv.Atoi(match[1])
+		if err != nil {
+			return "", 0, false
+		}
+		return strings.Join(append(parts[:i:i], parts[i+1:]...), "/"), size, true
+	}
+	return "", 0, false
+}
+
+// LoadBenchmarks reads the results of go test -bench output from the given
+// filename (see ReadBenchmarks).
+func LoadBenchmarks(filename string) ([]Benchmark, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error opening benchmark results file")
+	}
+	defer f.Close()
+	return ReadBenchmarks(f)
+}
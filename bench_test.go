@@ -0,0 +1,45 @@
+package fnplot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const benchOutput = `goos: linux
+goarch: amd64
+pkg: example.com/sort
+BenchmarkSort/size=10-8         	 5000000	       250 ns/op	      80 B/op	       1 allocs/op
+BenchmarkSort/size=100-8        	  500000	      3100 ns/op	     896 B/op	       1 allocs/op
+BenchmarkSearch/sorted/1000-8   	 2000000	       610 ns/op
+BenchmarkSearch/sorted/10-8     	 9000000	       120 ns/op
+BenchmarkNoSize-8               	 1000000	      1000 ns/op
+PASS
+ok  	example.com/sort	10.123s
+`
+
+func TestReadBenchmarks(t *testing.T) {
+	benchmarks, err := ReadBenchmarks(strings.NewReader(benchOutput))
+	require.NoError(t, err, "Error reading benchmarks")
+	require.Len(t, benchmarks, 2, "Expected the benchmarks with sizes")
+
+	sort := benchmarks[0]
+	assert.Equal(t, "BenchmarkSort", sort.Name, "Expected the name without the size")
+	require.Len(t, sort.Metrics, 3, "Expected a set for each unit")
+	inputs, outputs := setFloats(t, sort.Metrics["ns/op"])
+	assert.Equal(t, []float64{10, 100}, inputs, "Expected the sizes")
+	assert.Equal(t, []float64{250, 3100}, outputs, "Expected the ns/op measurements")
+	_, outputs = setFloats(t, sort.Metrics["B/op"])
+	assert.Equal(t, []float64{80, 896}, outputs, "Expected the B/op measurements")
+
+	search := benchmarks[1]
+	assert.Equal(t, "BenchmarkSearch/sorted", search.Name, "Expected the name without the size")
+	inputs, outputs = setFloats(t, search.Metrics["ns/op"])
+	assert.Equal(t, []float64{1000, 10}, inputs, "Expected the sizes")
+	assert.Equal(t, []float64{610, 120}, outputs, "Expected the ns/op measurements")
+
+	_, err = ReadBenchmarks(strings.NewReader("BenchmarkBad/10-8 100 fast ns/op\n"))
+	assert.Error(t, err, "Expected an error for an invalid measurement")
+}
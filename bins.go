@@ -0,0 +1,173 @@
+package fnplot
+
+import (
+	"image/color"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/plotutil"
+	"gonum.org/v1/plot/vg"
+)
+
+const (
+	// defaultBins is the number of X buckets used by the BoxPlot and
+	// PercentileBands plot styles if the plot doesn't specify a number.
+	defaultBins = 20
+	// defaultDensityBins is the number of X buckets used by the Density plot
+	// style if the plot doesn't specify a number.
+	defaultDensityBins = 100
+)
+
+// bandPercentiles are the percentiles drawn by the PercentileBands plot style.
+var bandPercentiles = []float64{50, 95, 99}
+
+// A bin holds the Y values of the points in a range of X values.
+type bin struct {
+	// x is the X value at the center of the bin.
+	x  float64
+	ys []float64
+}
+
+// binPoints groups the points into n equal width bins between min and max X.
+// Points outside of the range are ignored. The Y values in each bin are sorted.
+func binPoints(points plotter.XYs, min, max float64, n int) []bin {
+	width := (max - min) / float64(n)
+	if width == 0 {
+		width = 1
+	}
+	bins := make([]bin, n)
+	for i := range bins {
+		bins[i].x = min + (float64(i)+0.5)*width
+	}
+	for _, p := range points {
+		if p.X < min || p.X > max {
+			continue
+		}
+		i := int((p.X - min) / width)
+		if i >= n {
+			i = n - 1
+		}
+		bins[i].ys = append(bins[i].ys, p.Y)
+	}
+	for i := range bins {
+		sort.Float64s(bins[i].ys)
+	}
+	return bins
+}
+
+// percentile returns the p-th percentile (between 0 and 100) of the sorted
+// values using the nearest-rank method.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return math.NaN()
+	}
+	return sorted[rankIndex(len(sorted), p)]
+}
+
+// xRange returns the smallest and largest X values of all points.
+func xRange(points []plotter.XYs) (min, max float64) {
+	min, max = math.Inf(1), math.Inf(-1)
+	for _, pts := range points {
+		for _, p := range pts {
+			min, max = math.Min(min, p.X), math.Max(max, p.X)
+		}
+	}
+	return min, max
+}
+
+// addBinned groups the points of each named series into X bins and adds them to
+// the plot as box plots or percentile bands, depending on the plot style. Each
+// series is drawn in the color of the series style at the same index. The box
+// plots are sized to fit the given width of the plot image.
+func addBinned(p *plot.Plot, style PlotStyle, styles []SeriesStyle, bins int, names []string, points []plotter.XYs, width vg.Length) error {
+	if bins <= 0 {
+		bins = defaultBins
+	}
+	min, max := xRange(points)
+	if math.IsInf(min, 0) || math.IsInf(max, 0) {
+		return plotter.ErrInfinity
+	}
+
+	for i := range points {
+		binned := binPoints(points[i], min, max, bins)
+		var err error
+		switch style {
+		case BoxPlot:
+			err = addBoxPlots(p, i, len(points), styles[i].Color, names[i], binned, width)
+		case PercentileBands:
+			err = addPercentileBands(p, styles[i].Color, names[i], binned)
+		default:
+			err = errors.Errorf("plot style %d is not a binned style", style)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// boxWidth returns the width of each box of n series of box plots with the
+// given number of bins, so that all of the boxes fit into about 60% of the
+// width of the plot area, which is about 90% of the width of the image.
+func boxWidth(plotWidth vg.Length, bins, n int) vg.Length {
+	return 0.6 * 0.9 * plotWidth / vg.Length(bins*n)
+}
+
+// addBoxPlots draws a box plot in the given color for each non-empty bin of
+// series i of n. The boxes of each series are offset so that they're drawn side
+// by side, and are sized to fit the given width of the plot image.
+func addBoxPlots(p *plot.Plot, i, n int, c color.Color, name string, bins []bin, plotWidth vg.Length) error {
+	width := boxWidth(plotWidth, len(bins), n)
+	empty := true
+	for _, b := range bins {
+		if len(b.ys) == 0 {
+			continue
+		}
+		box, err := plotter.NewBoxPlot(width, b.x, plotter.Values(b.ys))
+		if err != nil {
+			return errors.WithMessage(err, "error creating box plot for series "+name)
+		}
+		box.Offset = (vg.Length(i) - vg.Length(n-1)/2) * width
+		box.BoxStyle.Color = c
+		box.MedianStyle.Color = c
+		box.WhiskerStyle.Color = c
+		box.GlyphStyle.Color = c
+		p.Add(box)
+		empty = false
+	}
+	if !empty {
+		// Box plots can't be drawn in the legend, so use a line of the same
+		// color.
+		thumbnail := &plotter.Line{LineStyle: plotter.DefaultLineStyle}
+		thumbnail.Color = c
+		p.Legend.Add(name, thumbnail)
+	}
+	return nil
+}
+
+// addPercentileBands draws a line in the given color through the 50th, 95th,
+// and 99th percentile of the Y values of each non-empty bin.
+func addPercentileBands(p *plot.Plot, c color.Color, name string, bins []bin) error {
+	for j, pct := range bandPercentiles {
+		var band plotter.XYs
+		for _, b := range bins {
+			if len(b.ys) == 0 {
+				continue
+			}
+			band = append(band, plotter.XY{X: b.x, Y: percentile(b.ys, pct)})
+		}
+		line, err := plotter.NewLine(band)
+		if err != nil {
+			return err
+		}
+		line.Color = c
+		line.Dashes = plotutil.Dashes(j)
+		p.Add(line)
+		p.Legend.Add(name+" p"+strconv.FormatFloat(pct, 'g', -1, 64), line)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package fnplot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+func TestBinPoints(t *testing.T) {
+	points := plotter.XYs{
+		{X: 0, Y: 3},
+		{X: 1, Y: 1},
+		{X: 4.9, Y: 2},
+		{X: 5, Y: 10},
+		{X: 10, Y: 20},
+		{X: 11, Y: 30},
+	}
+	bins := binPoints(points, 0, 10, 2)
+	assert.Equal(t, []bin{
+		{x: 2.5, ys: []float64{1, 2, 3}},
+		{x: 7.5, ys: []float64{10, 20}},
+	}, bins, "Expected and actual bins are different")
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	assert.Equal(t, float64(5), percentile(sorted, 50), "Expected and actual 50th percentiles are different")
+	assert.Equal(t, float64(10), percentile(sorted, 95), "Expected and actual 95th percentiles are different")
+	assert.Equal(t, float64(1), percentile(sorted, 0), "Expected and actual 0th percentiles are different")
+}
+
+func TestBoxWidth(t *testing.T) {
+	assert.Equal(t, 0.54*20*vg.Inch/40, boxWidth(20*vg.Inch, 20, 2), "Expected and actual box widths are different")
+	assert.Equal(t, boxWidth(20*vg.Inch, 20, 2)/2, boxWidth(10*vg.Inch, 20, 2), "Expected the box width to scale with the plot width")
+}
+
+func TestRankIndex(t *testing.T) {
+	tests := []struct {
+		n        int
+		p        float64
+		expected int
+	}{
+		{n: 10, p: 0, expected: 0},
+		{n: 10, p: 50, expected: 4},
+		{n: 10, p: 95, expected: 9},
+		{n: 10, p: 100, expected: 9},
+		{n: 1, p: 50, expected: 0},
+		{n: 4, p: 200, expected: 3},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, rankIndex(test.n, test.p), "Expected and actual indexes of the %vth percentile of %d values are different", test.p, test.n)
+	}
+}
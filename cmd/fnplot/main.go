@@ -0,0 +1,158 @@
+// Command fnplot plots data saved by fnplot without writing any Go code. It
+// plots sets saved with SaveJSON or SaveGob, CSV files written with WriteCSV,
+// and the output of go test -bench, each file as a series of the plot:
+//
+//	fnplot -y ln -style scatter -o hash.png hash.json
+//	fnplot -metric B/op -o allocs.svg bench.txt
+//	fnplot -serve localhost:8080 samples.gob
+//
+// The format of each file is determined by its extension: ".json", ".gob", and
+// ".csv" files are sets, and any other file is go test -bench output, with a
+// series for each benchmark. The plot is configured by a JSON plot config file
+// (see fnplot.LoadPlotConfig) given with -config, and by the flags, which
+// override the config file.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/matthewdale/fnplot"
+	"github.com/pkg/errors"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stderr); err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintln(os.Stderr, "fnplot:", err)
+		}
+		os.Exit(2)
+	}
+}
+
+// run plots the files named by the arguments as configured by the flags in the
+// arguments. Usage and flag errors are written to stderr.
+func run(args []string, stderr io.Writer) error {
+	flags := flag.NewFlagSet("fnplot", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: fnplot [flags] file...")
+		flags.PrintDefaults()
+	}
+	var (
+		configFile = flags.String("config", "", "JSON plot config `file`")
+		title      = flags.String("title", "", "plot title")
+		xLabel     = flags.String("xlabel", "", "X axis label")
+		yLabel     = flags.String("ylabel", "", "Y axis label")
+		xAxis      = flags.String("x", "", "X axis: std, ln, rank, normalized, time, duration, or auto")
+		yAxis      = flags.String("y", "", "Y axis: std, ln, rank, normalized, time, duration, or auto")
+		style      = flags.String("style", "", "plot style, like linePoints, scatter, or boxPlot")
+		theme      = flags.String("theme", "", "theme: light, dark, or highContrast")
+		width      = flags.Float64("width", 0, "image width in inches")
+		height     = flags.Float64("height", 0, "image height in inches")
+		metric     = flags.String("metric", "ns/op", "benchmark `unit` to plot, like ns/op, B/op, or allocs/op")
+		output     = flags.String("o", "", "image `file` to write, like plot.png (default plot.png)")
+		serve      = flags.String("serve", "", "serve the plot over HTTP at `addr` instead of writing an image")
+	)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("no files to plot")
+	}
+
+	var config fnplot.PlotConfig
+	if *configFile != "" {
+		var err error
+		if config, err = fnplot.LoadPlotConfig(*configFile); err != nil {
+			return err
+		}
+	}
+	override(&config.Title, *title)
+	override(&config.XLabel, *xLabel)
+	override(&config.YLabel, *yLabel)
+	override(&config.XAxis, *xAxis)
+	override(&config.YAxis, *yAxis)
+	override(&config.Style, *style)
+	override(&config.Theme, *theme)
+	override(&config.Output, *output)
+	if *width != 0 {
+		config.Width = *width
+	}
+	if *height != 0 {
+		config.Height = *height
+	}
+	if config.Output == "" {
+		config.Output = "plot.png"
+	}
+
+	var opts []fnplot.PlotOption
+	for _, filename := range flags.Args() {
+		series, err := load(filename, *metric)
+		if err != nil {
+			return errors.WithMessage(err, "error loading "+filename)
+		}
+		opts = append(opts, series...)
+	}
+	pl, err := config.NewPlot(opts...)
+	if err != nil {
+		return err
+	}
+	if *serve != "" {
+		return fnplot.Serve(*serve, pl, 0, 0)
+	}
+	return pl.Save(config.Output)
+}
+
+// override sets the config field to the flag value, if the flag is set.
+func override(field *string, value string) {
+	if value != "" {
+		*field = value
+	}
+}
+
+// load reads the file and returns the options that add each set in the file as
+// a series of the plot. Benchmarks are plotted by the given metric.
+func load(filename, metric string) ([]fnplot.PlotOption, error) {
+	name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	var set *fnplot.ValuesSet
+	var err error
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		set, err = fnplot.LoadJSON(filename)
+	case ".gob":
+		set, err = fnplot.LoadGob(filename)
+	case ".csv":
+		set, err = fnplot.LoadCSV(filename)
+	default:
+		return loadBenchmarks(filename, metric)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []fnplot.PlotOption{fnplot.WithSeries(name, fnplot.FnOf(set))}, nil
+}
+
+// loadBenchmarks reads go test -bench output and returns the options that add
+// the given metric of each benchmark as a series of the plot.
+func loadBenchmarks(filename, metric string) ([]fnplot.PlotOption, error) {
+	benchmarks, err := fnplot.LoadBenchmarks(filename)
+	if err != nil {
+		return nil, err
+	}
+	var opts []fnplot.PlotOption
+	for _, b := range benchmarks {
+		if set, ok := b.Metrics[metric]; ok {
+			opts = append(opts, fnplot.WithSeries(b.Name, fnplot.FnOf(set)))
+		}
+	}
+	if len(opts) == 0 {
+		return nil, errors.New("no benchmarks with sizes have " + metric + " results")
+	}
+	return opts, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matthewdale/fnplot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const benchOutput = `BenchmarkSort/size=10-8    5000000    250 ns/op    80 B/op
+BenchmarkSort/size=100-8    500000   3100 ns/op   896 B/op
+`
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fnplot")
+	require.NoError(t, err, "Error creating temporary directory")
+	defer os.RemoveAll(dir)
+	file := func(name string) string { return filepath.Join(dir, name) }
+
+	set := fnplot.NewFn(math.Sqrt, 20, fnplot.Float64Range(1, 100)).ValuesSet()
+	require.NoError(t, set.SaveJSON(file("sqrt.json")), "Error saving JSON")
+	require.NoError(t, set.SaveGob(file("sqrt.gob")), "Error saving gob")
+	csvFile, err := os.Create(file("sqrt.csv"))
+	require.NoError(t, err, "Error creating CSV file")
+	require.NoError(t, set.WriteCSV(csvFile), "Error writing CSV")
+	require.NoError(t, csvFile.Close(), "Error closing CSV file")
+	require.NoError(t, ioutil.WriteFile(file("bench.txt"), []byte(benchOutput), 0644), "Error writing benchmarks")
+	require.NoError(t, ioutil.WriteFile(file("plot.json"), []byte(`{"title": "Sqrt", "style": "scatter"}`), 0644),
+		"Error writing plot config")
+
+	tests := []struct {
+		description string
+		args        []string
+		output      string
+		err         string
+	}{
+		{
+			description: "sets",
+			args:        []string{"-y", "ln", "-o", file("sets.png"), file("sqrt.json"), file("sqrt.gob"), file("sqrt.csv")},
+			output:      file("sets.png"),
+		},
+		{
+			description: "config",
+			args:        []string{"-config", file("plot.json"), "-o", file("config.svg"), file("sqrt.json")},
+			output:      file("config.svg"),
+		},
+		{
+			description: "benchmarks",
+			args:        []string{"-metric", "B/op", "-o", file("bench.pdf"), file("bench.txt")},
+			output:      file("bench.pdf"),
+		},
+		{description: "no files", args: []string{}, err: "no files to plot"},
+		{description: "missing file", args: []string{file("missing.json")}, err: "error loading"},
+		{description: "missing metric", args: []string{"-metric", "MB/s", file("bench.txt")}, err: "MB/s"},
+		{description: "unknown axis", args: []string{"-x", "log", file("sqrt.json")}, err: `unknown axis "log"`},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			var stderr strings.Builder
+			err := run(test.args, &stderr)
+			if test.err != "" {
+				require.Error(t, err, "Expected an error")
+				assert.Contains(t, err.Error(), test.err, "Expected the error message")
+				return
+			}
+			require.NoError(t, err, "Error running fnplot")
+			info, err := os.Stat(test.output)
+			require.NoError(t, err, "Expected the plot image")
+			assert.NotZero(t, info.Size(), "Expected the plot image to not be empty")
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package fnplot
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/plot/plotter"
+)
+
+// A ComplexityClass is an asymptotic complexity class, like O(n log n).
+type ComplexityClass struct {
+	// Name is the big-O notation of the class, like "O(n log n)".
+	Name string
+	// Cost returns the relative cost of an input of size n.
+	Cost func(n float64) float64
+}
+
+// ComplexityClasses are the complexity classes considered by
+// EstimateComplexity, in increasing order of growth.
+var ComplexityClasses = []ComplexityClass{
+	{Name: "O(1)", Cost: func(float64) float64 { return 1 }},
+	{Name: "O(log n)", Cost: math.Log},
+	{Name: "O(n)", Cost: func(n float64) float64 { return n }},
+	{Name: "O(n log n)", Cost: func(n float64) float64 { return n * math.Log(n) }},
+	{Name: "O(n²)", Cost: func(n float64) float64 { return n * n }},
+	{Name: "O(n³)", Cost: func(n float64) float64 { return n * n * n }},
+	{Name: "O(2ⁿ)", Cost: func(n float64) float64 { return math.Exp2(n) }},
+}
+
+// A ComplexityEstimate is the complexity class that best fits a set of input
+// sizes and measured costs.
+type ComplexityEstimate struct {
+	Class ComplexityClass
+	// Coefficient and Constant are the fitted cost model, cost = Coefficient *
+	// Class.Cost(n) + Constant.
+	Coefficient, Constant float64
+	// RSquared is the coefficient of determination (R²) of the fitted cost
+	// model. The closer it is to 1, the better the class fits the costs.
+	RSquared float64
+}
+
+func (ce ComplexityEstimate) String() string {
+	return fmt.Sprintf("%s (R² = %.4f)", ce.Class.Name, ce.RSquared)
+}
+
+// EstimateComplexity estimates the complexity class of a function from a set
+// of input sizes (the input scalar values) and measured costs (the output
+// scalar values), like the number of comparisons made by a sort function for a
+// slice of length n.
+//
+// A cost model, cost = a * f(n) + b, is fit by least squares for each of the
+// ComplexityClasses, and the class with the lowest AIC is returned. Classes
+// that are undefined for some input sizes (e.g. O(log n) for n = 0) are
+// skipped.
+func EstimateComplexity(set *ValuesSet) (ComplexityEstimate, error) {
+	inputs, outputs, err := set.scalars()
+	if err != nil {
+		return ComplexityEstimate{}, errors.WithMessage(err, "error converting values")
+	}
+	points := make(plotter.XYs, len(inputs))
+	for i := range inputs {
+		points[i].X, _ = inputs[i].Float64()
+		points[i].Y, _ = outputs[i].Float64()
+	}
+	return estimateComplexity(points)
+}
+
+func estimateComplexity(points plotter.XYs) (ComplexityEstimate, error) {
+	if len(points) == 0 {
+		return ComplexityEstimate{}, errors.WithMessage(ErrNoSamples, "cannot estimate complexity without any values")
+	}
+	var meanY float64
+	for i := range points {
+		meanY += points[i].Y
+	}
+	meanY /= float64(len(points))
+
+	var best ComplexityEstimate
+	bestAIC := math.Inf(1)
+	found := false
+	for _, class := range ComplexityClasses {
+		costs := make(plotter.XYs, len(points))
+		defined := true
+		for i := range points {
+			costs[i].X = class.Cost(points[i].X)
+			costs[i].Y = points[i].Y
+			if math.IsNaN(costs[i].X) || math.IsInf(costs[i].X, 0) {
+				defined = false
+				break
+			}
+		}
+		if !defined {
+			continue
+		}
+
+		estimate := ComplexityEstimate{Class: class}
+		k := 2
+		if fit, err := FitLinear(costs); err == nil {
+			estimate.Coefficient, estimate.Constant = fit.Slope, fit.Intercept
+		} else {
+			// The class has the same cost for every input size, so the cost
+			// model has only a constant.
+			k = 1
+			estimate.Constant = meanY
+		}
+		model := func(n float64) float64 { return estimate.Coefficient*n + estimate.Constant }
+		estimate.RSquared = rSquared(costs, meanY, model)
+
+		var rss float64
+		for i := range costs {
+			rss += (costs[i].Y - model(costs[i].X)) * (costs[i].Y - model(costs[i].X))
+		}
+		aic := float64(len(costs))*math.Log(rss/float64(len(costs))) + 2*float64(k)
+		if !found || aic < bestAIC {
+			best, bestAIC, found = estimate, aic, true
+		}
+	}
+	if !found {
+		return ComplexityEstimate{}, errors.New("no complexity class could be fit to the values")
+	}
+	return best, nil
+}
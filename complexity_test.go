@@ -0,0 +1,62 @@
+package fnplot
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateComplexity(t *testing.T) {
+	tests := []struct {
+		description string
+		cost        func(n int) float64
+		expected    string
+	}{
+		{
+			description: "Constant",
+			cost:        func(n int) float64 { return 42 + float64(n%2) },
+			expected:    "O(1)",
+		},
+		{
+			description: "Logarithmic",
+			cost:        func(n int) float64 { return 3 * math.Log(float64(n)) },
+			expected:    "O(log n)",
+		},
+		{
+			description: "Linear",
+			cost:        func(n int) float64 { return 5*float64(n) + 10 },
+			expected:    "O(n)",
+		},
+		{
+			description: "Linearithmic",
+			cost:        func(n int) float64 { return 2 * float64(n) * math.Log(float64(n)) },
+			expected:    "O(n log n)",
+		},
+		{
+			description: "Quadratic",
+			cost:        func(n int) float64 { return float64(n*n) / 2 },
+			expected:    "O(n²)",
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			set := &ValuesSet{}
+			for n := 1; n <= 1000; n += 7 {
+				require.NoError(t, set.insert(NewValues(n), NewValues(test.cost(n))), "Error inserting values")
+			}
+			estimate, err := EstimateComplexity(set)
+			require.NoError(t, err, "Error estimating complexity")
+			assert.Equal(t, test.expected, estimate.Class.Name, "Expected and actual complexity classes are different")
+		})
+	}
+}
+
+func TestEstimateComplexityEmpty(t *testing.T) {
+	_, err := EstimateComplexity(NewValuesSet(nil))
+	require.Error(t, err, "Expected an error estimating the complexity of an empty set")
+	assert.Equal(t, ErrNoSamples, errors.Cause(err), "Expected the cause of the error")
+}
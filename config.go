@@ -0,0 +1,181 @@
+package fnplot
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/plot/vg"
+)
+
+// A PlotConfig is a declarative plot configuration, read from a JSON file with
+// LoadPlotConfig, so the appearance of a plot can be changed without
+// recompiling the program that samples the function. For example:
+//
+//	{
+//	  "title": "Hash outputs",
+//	  "xLabel": "input",
+//	  "yLabel": "output",
+//	  "xAxis": "std",
+//	  "yAxis": "rank",
+//	  "samples": 5000,
+//	  "width": 10,
+//	  "height": 4,
+//	  "style": "scatter",
+//	  "theme": "dark",
+//	  "output": "hash.png"
+//	}
+type PlotConfig struct {
+	Title  string `json:"title"`
+	XLabel string `json:"xLabel"`
+	YLabel string `json:"yLabel"`
+	// XAxis and YAxis are the names of the X and Y axes: "std", "ln", "rank",
+	// "normalized", "time", "duration", or "auto". If empty, "std" is used.
+	XAxis string `json:"xAxis"`
+	YAxis string `json:"yAxis"`
+	// Samples is the number of times the function set with WithFunc is
+	// sampled.
+	Samples int `json:"samples"`
+	// Width and Height are the size of the plot images in inches. If zero, the
+	// default size is used.
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	// Style is the name of the plot style: "linePoints", "line", "scatter",
+	// "boxPlot", "percentileBands", "density", "errorBars", or "band". If
+	// empty, "linePoints" is used.
+	Style string `json:"style"`
+	// Theme is the name of the theme: "light", "dark", or "highContrast". If
+	// empty, the gonum defaults are used.
+	Theme string `json:"theme"`
+	// Output is the filename to save the plot image to, like "plot.png".
+	Output string `json:"output"`
+}
+
+// configAxes create the axes by their name in a PlotConfig.
+var configAxes = map[string]func() Axis{
+	"std":        func() Axis { return &StdAxix{} },
+	"ln":         func() Axis { return &LnAxis{} },
+	"rank":       func() Axis { return &RankAxis{} },
+	"normalized": func() Axis { return &NormalizedAxis{} },
+	"time":       func() Axis { return &TimeAxis{} },
+	"duration":   func() Axis { return &DurationAxis{} },
+	"auto":       func() Axis { return &AutoAxis{} },
+}
+
+// configStyles are the plot styles by their name in a PlotConfig.
+var configStyles = map[string]PlotStyle{
+	"linePoints":      LinePoints,
+	"line":            Line,
+	"scatter":         Scatter,
+	"boxPlot":         BoxPlot,
+	"percentileBands": PercentileBands,
+	"density":         Density,
+	"errorBars":       ErrorBars,
+	"band":            Band,
+}
+
+// configThemes are the themes by their name in a PlotConfig.
+var configThemes = map[string]Theme{
+	"light":        LightTheme,
+	"dark":         DarkTheme,
+	"highContrast": HighContrastTheme,
+}
+
+// ReadPlotConfig reads a JSON plot configuration from r. Unknown fields are
+// an error so that misspelled settings aren't silently ignored.
+func ReadPlotConfig(r io.Reader) (PlotConfig, error) {
+	var c PlotConfig
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&c); err != nil {
+		return PlotConfig{}, errors.WithMessage(err, "error decoding plot config JSON")
+	}
+	if _, err := c.Options(); err != nil {
+		return PlotConfig{}, err
+	}
+	return c, nil
+}
+
+// LoadPlotConfig reads a JSON plot configuration from the given filename (see
+// ReadPlotConfig).
+func LoadPlotConfig(filename string) (PlotConfig, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return PlotConfig{}, errors.WithMessage(err, "error opening plot config file")
+	}
+	defer f.Close()
+	return ReadPlotConfig(f)
+}
+
+// Options returns the plot options of the configuration, or an error if it
+// names an unknown axis, style, or theme.
+func (c PlotConfig) Options() ([]PlotOption, error) {
+	x, err := configAxis(c.XAxis)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid X axis")
+	}
+	y, err := configAxis(c.YAxis)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid Y axis")
+	}
+	opts := []PlotOption{
+		WithTitle(c.Title),
+		WithLabels(c.XLabel, c.YLabel),
+		WithAxes(x, y),
+		WithSamples(c.Samples),
+		WithSize(vg.Length(c.Width)*vg.Inch, vg.Length(c.Height)*vg.Inch),
+	}
+	if c.Style != "" {
+		style, ok := configStyles[c.Style]
+		if !ok {
+			return nil, errors.Errorf("unknown plot style %q, expected one of %s", c.Style, configNames(configStyles))
+		}
+		opts = append(opts, WithStyle(style))
+	}
+	if c.Theme != "" {
+		th, ok := configThemes[c.Theme]
+		if !ok {
+			return nil, errors.Errorf("unknown theme %q, expected one of %s", c.Theme, configNames(configThemes))
+		}
+		opts = append(opts, WithTheme(th))
+	}
+	return opts, nil
+}
+
+// NewPlot creates a plot configured by the configuration and then by the given
+// options, like WithFunc or WithFn for the function to plot (see NewPlot).
+// Save the plot to Output with Save.
+func (c PlotConfig) NewPlot(opts ...PlotOption) (Plot, error) {
+	configOpts, err := c.Options()
+	if err != nil {
+		return Plot{}, err
+	}
+	return NewPlot(append(configOpts, opts...)...)
+}
+
+// configAxis creates the named axis, or a StdAxix if the name is empty.
+func configAxis(name string) (Axis, error) {
+	if name == "" {
+		name = "std"
+	}
+	newAxis, ok := configAxes[name]
+	if !ok {
+		return nil, errors.Errorf("unknown axis %q, expected one of %s", name, configNames(configAxes))
+	}
+	return newAxis(), nil
+}
+
+// configNames returns the sorted names of a PlotConfig map, joined for error
+// messages.
+func configNames(m interface{}) string {
+	var names []string
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		names = append(names, key.String())
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
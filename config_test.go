@@ -0,0 +1,75 @@
+package fnplot
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gonum.org/v1/plot/vg"
+)
+
+func TestLoadPlotConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fnplot")
+	require.NoError(t, err, "Error creating temporary directory")
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "plot.json")
+	err = ioutil.WriteFile(filename, []byte(`{
+		"title": "math.Sqrt",
+		"xLabel": "x",
+		"yLabel": "sqrt(x)",
+		"yAxis": "ln",
+		"samples": 30,
+		"width": 6,
+		"height": 3,
+		"style": "scatter",
+		"theme": "dark",
+		"output": "sqrt.png"
+	}`), 0644)
+	require.NoError(t, err, "Error writing plot config file")
+
+	config, err := LoadPlotConfig(filename)
+	require.NoError(t, err, "Error loading plot config")
+	assert.Equal(t, "sqrt.png", config.Output, "Expected the output filename")
+
+	pl, err := config.NewPlot(WithFunc(math.Sqrt, Float64Range(1, 100)))
+	require.NoError(t, err, "Error creating plot")
+	assert.Equal(t, "math.Sqrt", pl.Title, "Expected the title")
+	assert.Equal(t, "sqrt(x)", pl.YLabel, "Expected the Y label")
+	assert.IsType(t, &StdAxix{}, pl.X, "Expected the default X axis")
+	assert.IsType(t, &LnAxis{}, pl.Y, "Expected the Y axis")
+	assert.Equal(t, 30, pl.Fn.ValuesSet().Len(), "Expected the function to be sampled")
+	assert.Equal(t, 6*vg.Inch, pl.Width, "Expected the width")
+	assert.Equal(t, 3*vg.Inch, pl.Height, "Expected the height")
+	assert.Equal(t, Scatter, pl.Style, "Expected the style")
+	assert.Equal(t, DarkTheme, pl.Theme, "Expected the theme")
+	var buf bytes.Buffer
+	assert.NoError(t, pl.Write(&buf, "svg"), "Error writing plot")
+}
+
+func TestReadPlotConfigErrors(t *testing.T) {
+	tests := []struct {
+		description string
+		config      string
+		err         string
+	}{
+		{description: "invalid JSON", config: `{"title":`, err: "error decoding plot config JSON"},
+		{description: "unknown field", config: `{"colour": "red"}`, err: "unknown field"},
+		{description: "unknown axis", config: `{"xAxis": "log"}`, err: `unknown axis "log"`},
+		{description: "unknown style", config: `{"style": "bars"}`, err: `unknown plot style "bars"`},
+		{description: "unknown theme", config: `{"theme": "solarized"}`, err: `unknown theme "solarized"`},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			_, err := ReadPlotConfig(strings.NewReader(test.config))
+			require.Error(t, err, "Expected an error reading the plot config")
+			assert.Contains(t, err.Error(), test.err, "Expected the error message")
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package fnplot
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// WriteCSV writes the input and output scalar values of every pair in the set
+// as CSV, one pair per row, after a header row.
+func (set *ValuesSet) WriteCSV(w io.Writer) error {
+	return set.writeCSV(w, false)
+}
+
+// WriteFormattedCSV writes the input and output scalar values of every pair in
+// the set as CSV like WriteCSV, followed by the formatted input and output
+// values that the scalars were converted from.
+func (set *ValuesSet) WriteFormattedCSV(w io.Writer) error {
+	return set.writeCSV(w, true)
+}
+
+func (set *ValuesSet) writeCSV(w io.Writer, formatted bool) error {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	samples, err := set.samplesLocked()
+	if err != nil {
+		return err
+	}
+	inputs, outputs, err := sampleScalars(samples)
+	if err != nil {
+		return err
+	}
+	var formattedInputs, formattedOutputs []string
+	header := []string{"input", "output"}
+	if formatted {
+		formattedInputs, formattedOutputs = sampleStrings(samples)
+		header = append(header, "input_values", "output_values")
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return errors.WithMessage(err, "error writing CSV header")
+	}
+	for i := range inputs {
+		record := []string{inputs[i].Text('g', -1), outputs[i].Text('g', -1)}
+		if formatted {
+			record = append(record, formattedInputs[i], formattedOutputs[i])
+		}
+		if err := cw.Write(record); err != nil {
+			return errors.WithMessage(err, "error writing CSV row")
+		}
+	}
+	cw.Flush()
+	return errors.WithMessage(cw.Error(), "error writing CSV")
+}
+
+// ReadCSV reads a set written by WriteCSV or WriteFormattedCSV. Each pair
+// keeps its formatted values as strings, or its scalar values formatted as
+// strings if the CSV has no formatted values.
+func ReadCSV(r io.Reader) (*ValuesSet, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error reading CSV header")
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+	input, hasInput := columns["input"]
+	output, hasOutput := columns["output"]
+	if !hasInput || !hasOutput {
+		return nil, errors.New("CSV header must have input and output columns")
+	}
+	inputValues, hasInputValues := columns["input_values"]
+	outputValues, hasOutputValues := columns["output_values"]
+
+	set := &ValuesSet{}
+	for row := 1; ; row++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return set, nil
+		}
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("error reading CSV row %d", row))
+		}
+		s := Sample{
+			Input:  NewValues(record[input]),
+			Output: NewValues(record[output]),
+		}
+		if hasInputValues {
+			s.Input = NewValues(record[inputValues])
+		}
+		if hasOutputValues {
+			s.Output = NewValues(record[outputValues])
+		}
+		if s.InputScalar, err = parseScalar(record[input]); err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("error parsing input of CSV row %d", row))
+		}
+		if s.OutputScalar, err = parseScalar(record[output]); err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("error parsing output of CSV row %d", row))
+		}
+		if err := set.insertSample(s); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// LoadCSV reads a set written as CSV from the given filename (see ReadCSV).
+func LoadCSV(filename string) (*ValuesSet, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error opening CSV file")
+	}
+	defer f.Close()
+	return ReadCSV(f)
+}
@@ -0,0 +1,68 @@
+package fnplot
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCSV(t *testing.T) {
+	set := &ValuesSet{}
+	require.NoError(t, set.insert(NewValues(1.5), NewValues(2.25)), "Error inserting values")
+	require.NoError(t, set.insert(NewValues("a", "b"), NewValues(3.0)), "Error inserting values")
+
+	tests := []struct {
+		description string
+		write       func(*bytes.Buffer) error
+		expected    string
+	}{
+		{
+			description: "scalars",
+			write:       func(buf *bytes.Buffer) error { return set.WriteCSV(buf) },
+			expected:    "input,output\n1.5,2.25\n24930,3\n",
+		},
+		{
+			description: "formatted",
+			write:       func(buf *bytes.Buffer) error { return set.WriteFormattedCSV(buf) },
+			expected:    "input,output,input_values,output_values\n1.5,2.25,1.5,2.25\n24930,3,\"a, b\",3\n",
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, test.write(&buf), "Error writing CSV")
+			assert.Equal(t, test.expected, buf.String(), "Expected and actual CSV are different")
+		})
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	set := &ValuesSet{}
+	require.NoError(t, set.insert(NewValues(1.5), NewValues(2.25)), "Error inserting values")
+	require.NoError(t, set.insert(NewValues("a", "b"), NewValues(3.0)), "Error inserting values")
+
+	for _, formatted := range []bool{false, true} {
+		var buf bytes.Buffer
+		require.NoError(t, set.writeCSV(&buf, formatted), "Error writing CSV")
+		read, err := ReadCSV(&buf)
+		require.NoError(t, err, "Error reading CSV")
+		inputs, outputs := setFloats(t, read)
+		assert.Equal(t, []float64{1.5, 24930}, inputs, "Expected the input scalar values")
+		assert.Equal(t, []float64{2.25, 3}, outputs, "Expected the output scalar values")
+		formattedInputs, _, err := read.formatted()
+		require.NoError(t, err, "Error reading formatted values")
+		if formatted {
+			assert.Equal(t, "a, b", formattedInputs[1], "Expected the formatted input values")
+		} else {
+			assert.Equal(t, "24930", formattedInputs[1], "Expected the input scalar value")
+		}
+	}
+
+	_, err := ReadCSV(bytes.NewBufferString("x,y\n1,2\n"))
+	assert.Error(t, err, "Expected an error reading CSV without input and output columns")
+	_, err = ReadCSV(bytes.NewBufferString("input,output\n1,abc\n"))
+	assert.Error(t, err, "Expected an error reading a CSV output that isn't a number")
+}
@@ -0,0 +1,125 @@
+package fnplot
+
+import (
+	"math/big"
+	"sort"
+)
+
+// Derivative returns a new set with the discrete derivative of the set, the
+// change of the output scalar value divided by the change of the input scalar
+// value (ΔY/ΔX) between each pair of neighboring input scalar values. The
+// input of each new pair is the midpoint of the neighboring inputs. The
+// outputs of pairs with the same input are averaged first. Use FnOf to plot
+// the new set.
+func (set *ValuesSet) Derivative() (*ValuesSet, error) {
+	repeats, err := set.Repeats()
+	if err != nil {
+		return nil, err
+	}
+	derivative := &ValuesSet{}
+	for i := 1; i < len(repeats); i++ {
+		x0, x1 := repeats[i-1].InputScalar, repeats[i].InputScalar
+		dx := newFloat().Sub(x1, x0)
+		dy := newFloat().Sub(repeats[i].Output.Mean, repeats[i-1].Output.Mean)
+		mid := newFloat().Add(x0, x1)
+		mid.Quo(mid, big.NewFloat(2))
+		if err := derivative.insertScalars(mid, dy.Quo(dy, dx)); err != nil {
+			return nil, err
+		}
+	}
+	return derivative, nil
+}
+
+// DoublingRatio returns a new set with the ratio of the output scalar value at
+// double the input scalar value to the output scalar value at the input,
+// Y(2n)/Y(n), for each input whose double is in the range of the inputs. The
+// output at double an input is interpolated linearly between the neighboring
+// inputs, and the outputs of pairs with the same input are averaged first.
+// The ratio tends to 2 for O(n) functions, slightly more than 2 for O(n log n)
+// functions, and 4 for O(n²) functions. Inputs that aren't more than zero, or
+// whose output is zero, are skipped. Use FnOf to plot the new set.
+func (set *ValuesSet) DoublingRatio() (*ValuesSet, error) {
+	repeats, err := set.Repeats()
+	if err != nil {
+		return nil, err
+	}
+	ratios := &ValuesSet{}
+	j := 0
+	for _, r := range repeats {
+		if r.InputScalar.Sign() <= 0 || r.Output.Mean.Sign() == 0 {
+			continue
+		}
+		double := newFloat().Mul(r.InputScalar, big.NewFloat(2))
+		// Find the neighboring inputs of the double, which only increases.
+		for j < len(repeats)-1 && repeats[j+1].InputScalar.Cmp(double) <= 0 {
+			j++
+		}
+		if repeats[j].InputScalar.Cmp(double) > 0 {
+			continue
+		}
+		y := repeats[j].Output.Mean
+		if repeats[j].InputScalar.Cmp(double) != 0 {
+			if j == len(repeats)-1 {
+				break
+			}
+			y = interpolate(repeats[j], repeats[j+1], double)
+		}
+		if err := ratios.insertScalars(r.InputScalar, newFloat().Quo(y, r.Output.Mean)); err != nil {
+			return nil, err
+		}
+	}
+	return ratios, nil
+}
+
+// CumulativeSum returns a new set with the running sum of the output scalar
+// values of the set in the order of the input scalar values, like the total
+// work of a sequence of operations from the cost of each operation. Each pair
+// keeps its input, and its output is the sum of its output scalar value and
+// the output scalar values of every pair with a smaller input, or the same
+// input stored before it. Use FnOf to plot the new set.
+func (set *ValuesSet) CumulativeSum() (*ValuesSet, error) {
+	samples, err := set.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := sampleScalars(samples); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].InputScalar.Cmp(samples[j].InputScalar) == -1
+	})
+
+	sums := &ValuesSet{}
+	sum := newFloat()
+	for _, s := range samples {
+		sum = newFloat().Add(sum, s.OutputScalar)
+		s.Output = NewValues(sum)
+		s.OutputScalar = sum
+		if err := sums.insertSample(s); err != nil {
+			return nil, err
+		}
+	}
+	return sums, nil
+}
+
+// interpolate returns the mean output at x interpolated linearly between the
+// mean outputs of a and b.
+func interpolate(a, b Repeat, x *big.Float) *big.Float {
+	t := newFloat().Sub(x, a.InputScalar)
+	t.Quo(t, newFloat().Sub(b.InputScalar, a.InputScalar))
+	dy := newFloat().Sub(b.Output.Mean, a.Output.Mean)
+	return dy.Mul(dy, t).Add(dy, a.Output.Mean)
+}
+
+// insertScalars inserts a pair of derived scalar values into the set. The
+// values of the pair are the scalar values themselves.
+func (set *ValuesSet) insertScalars(input, output *big.Float) error {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	return set.insertSample(Sample{
+		Input:        NewValues(input),
+		Output:       NewValues(output),
+		InputScalar:  input,
+		OutputScalar: output,
+	})
+}
@@ -0,0 +1,63 @@
+package fnplot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDerivative(t *testing.T) {
+	set := newTestSet(t, []float64{0, 1, 3, 3, 4}, []float64{0, 1, 7, 11, 16})
+	derivative, err := set.Derivative()
+	require.NoError(t, err, "Error calculating derivative")
+	inputs, outputs := setFloats(t, derivative)
+	assert.Equal(t, []float64{0.5, 2, 3.5}, inputs, "Expected the midpoints of neighboring inputs")
+	assert.Equal(t, []float64{1, 4, 7}, outputs, "Expected the change of the mean outputs")
+}
+
+func TestDoublingRatio(t *testing.T) {
+	tests := []struct {
+		description string
+		f           func(x float64) float64
+		expected    float64
+	}{
+		{description: "linear", f: func(x float64) float64 { return 3 * x }, expected: 2},
+		{description: "quadratic", f: func(x float64) float64 { return x * x }, expected: 4},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			var inputs, outputs []float64
+			for x := 1.0; x <= 64; x *= 2 {
+				inputs = append(inputs, x)
+				outputs = append(outputs, test.f(x))
+			}
+			ratios, err := newTestSet(t, inputs, outputs).DoublingRatio()
+			require.NoError(t, err, "Error calculating doubling ratios")
+			ratioInputs, ratioOutputs := setFloats(t, ratios)
+			assert.Equal(t, []float64{1, 2, 4, 8, 16, 32}, ratioInputs, "Expected the inputs whose double was sampled")
+			for _, r := range ratioOutputs {
+				assert.InDelta(t, test.expected, r, 1e-9, "Expected the doubling ratio")
+			}
+		})
+	}
+
+	ratios, err := newTestSet(t, []float64{1, 3}, []float64{1, 5}).DoublingRatio()
+	require.NoError(t, err, "Error calculating doubling ratios")
+	_, ratioOutputs := setFloats(t, ratios)
+	assert.Equal(t, []float64{3}, ratioOutputs, "Expected the output at the double to be interpolated")
+}
+
+func TestCumulativeSum(t *testing.T) {
+	set := newTestSet(t, []float64{3, 1, 2, 1}, []float64{30, 10, 20, 5})
+	sums, err := set.CumulativeSum()
+	require.NoError(t, err, "Error calculating cumulative sum")
+	inputs, outputs := setFloats(t, sums)
+	assert.Equal(t, []float64{1, 1, 2, 3}, inputs, "Expected the inputs in order")
+	assert.Equal(t, []float64{10, 15, 35, 65}, outputs, "Expected the running sum of the outputs")
+
+	inputs, outputs = setFloats(t, set)
+	assert.Equal(t, []float64{3, 1, 2, 1}, inputs, "Expected the inputs of the set in their original order")
+	assert.Equal(t, []float64{30, 10, 20, 5}, outputs, "Expected the outputs of the set in their original order")
+}
@@ -0,0 +1,107 @@
+package fnplot
+
+import (
+	"bufio"
+	"encoding/gob"
+	"io"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// DiskStore is a SampleStore that appends the samples to a file so that runs
+// with more samples than fit in memory can still be plotted. Like the sets
+// read with ReadGob, the samples read from a DiskStore keep their formatted
+// input and output values as strings. Samples whose values couldn't be
+// converted keep the text of the conversion error.
+type DiskStore struct {
+	filename string
+	f        *os.File
+	w        *bufio.Writer
+	enc      *gob.Encoder
+	mm       minMax
+	n        int
+	// mu serializes flushing the buffered samples, which both Insert and
+	// Iterate do.
+	mu sync.Mutex
+}
+
+// NewDiskStore creates a SampleStore that stores the samples in the given file,
+// replacing the file if it exists. Close the store when it's no longer used.
+func NewDiskStore(filename string) (*DiskStore, error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error creating sample store file")
+	}
+	w := bufio.NewWriter(f)
+	return &DiskStore{
+		filename: filename,
+		f:        f,
+		w:        w,
+		enc:      gob.NewEncoder(w),
+	}, nil
+}
+
+func (ds *DiskStore) Insert(s Sample) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if err := ds.enc.Encode(newGobPair(s)); err != nil {
+		return errors.WithMessage(err, "error writing sample")
+	}
+	ds.mm.update(s)
+	ds.n++
+	return nil
+}
+
+func (ds *DiskStore) Iterate(fn func(s Sample) bool) error {
+	ds.mu.Lock()
+	err := ds.w.Flush()
+	ds.mu.Unlock()
+	if err != nil {
+		return errors.WithMessage(err, "error writing samples")
+	}
+
+	f, err := os.Open(ds.filename)
+	if err != nil {
+		return errors.WithMessage(err, "error opening sample store file")
+	}
+	defer f.Close()
+	dec := gob.NewDecoder(bufio.NewReader(f))
+	for {
+		var gp gobPair
+		err := dec.Decode(&gp)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.WithMessage(err, "error reading sample")
+		}
+		if !fn(gp.sample()) {
+			return nil
+		}
+	}
+}
+
+func (ds *DiskStore) MinMax() (minInput, maxInput, minOutput, maxOutput *big.Float) {
+	return ds.mm.minInput, ds.mm.maxInput, ds.mm.minOutput, ds.mm.maxOutput
+}
+
+func (ds *DiskStore) Len() int {
+	return ds.n
+}
+
+// Close writes any buffered samples and closes the file. The file isn't
+// removed, so it can be read later with ReadGob.
+func (ds *DiskStore) Close() error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if err := ds.w.Flush(); err != nil {
+		ds.f.Close()
+		return errors.WithMessage(err, "error writing samples")
+	}
+	return errors.WithMessage(ds.f.Close(), "error closing sample store file")
+}
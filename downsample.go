@@ -0,0 +1,62 @@
+package fnplot
+
+import (
+	"math"
+
+	"gonum.org/v1/plot/plotter"
+)
+
+// defaultMaxPoints is the number of points drawn for each series if the
+// MaxPoints of a Plot is zero.
+const defaultMaxPoints = 10000
+
+// DownsampleLTTB reduces the points, sorted by X, to n points with the
+// Largest-Triangle-Three-Buckets algorithm. The first and last points are
+// always kept, and the rest are chosen so that the shape of the line through
+// the points stays the same. If there are n or fewer points, or n is less
+// than 3, the points are returned unchanged.
+func DownsampleLTTB(points plotter.XYs, n int) plotter.XYs {
+	if n >= len(points) || n < 3 {
+		return points
+	}
+
+	downsampled := make(plotter.XYs, 0, n)
+	downsampled = append(downsampled, points[0])
+
+	// Split every point except the first and last into n-2 buckets and keep
+	// the point of each bucket that makes the largest triangle with the last
+	// kept point and the average point of the next bucket.
+	bucketSize := float64(len(points)-2) / float64(n-2)
+	last := 0
+	for i := 0; i < n-2; i++ {
+		start := int(float64(i)*bucketSize) + 1
+		end := int(float64(i+1)*bucketSize) + 1
+
+		nextStart := end
+		nextEnd := int(float64(i+2)*bucketSize) + 1
+		if nextEnd > len(points) {
+			nextEnd = len(points)
+		}
+		var avgX, avgY float64
+		for _, p := range points[nextStart:nextEnd] {
+			avgX += p.X
+			avgY += p.Y
+		}
+		avgX /= float64(nextEnd - nextStart)
+		avgY /= float64(nextEnd - nextStart)
+
+		maxArea := -1.0
+		chosen := start
+		a := points[last]
+		for j := start; j < end; j++ {
+			area := math.Abs((a.X-avgX)*(points[j].Y-a.Y) - (a.X-points[j].X)*(avgY-a.Y))
+			if area > maxArea {
+				maxArea = area
+				chosen = j
+			}
+		}
+		downsampled = append(downsampled, points[chosen])
+		last = chosen
+	}
+	return append(downsampled, points[len(points)-1])
+}
@@ -0,0 +1,37 @@
+package fnplot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot/plotter"
+)
+
+func TestDownsampleLTTB(t *testing.T) {
+	// A flat line with a single spike in the middle.
+	points := make(plotter.XYs, 101)
+	for i := range points {
+		points[i].X = float64(i)
+	}
+	points[50].Y = 10
+
+	tests := []struct {
+		description string
+		n           int
+		expectedLen int
+	}{
+		{description: "downsampled", n: 5, expectedLen: 5},
+		{description: "fewer points than n", n: 200, expectedLen: 101},
+		{description: "n too small", n: 2, expectedLen: 101},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			downsampled := DownsampleLTTB(points, test.n)
+			assert.Len(t, downsampled, test.expectedLen, "Expected and actual number of points are different")
+			assert.Equal(t, points[0], downsampled[0], "Expected the first point to be kept")
+			assert.Equal(t, points[100], downsampled[len(downsampled)-1], "Expected the last point to be kept")
+			assert.Contains(t, downsampled, points[50], "Expected the spike to be kept")
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package fnplot
+
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// ecdfPoints returns the points of the empirical cumulative distribution
+// function of the values on the axis. The first point is at a cumulative
+// fraction of 0 and each value adds 1/n.
+func ecdfPoints(values []*big.Float, axis Axis) plotter.XYs {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := sortedFloats(values)
+	points := make(plotter.XYs, len(sorted)+1)
+	points[0].X = axis.Point(sorted[0])
+	for i := range sorted {
+		points[i+1].X = axis.Point(sorted[i])
+		points[i+1].Y = float64(i+1) / float64(len(sorted))
+	}
+	return points
+}
+
+// ECDFOn returns the points of the empirical cumulative distribution function
+// (ECDF) of the output values of the set. The X coordinates are the output
+// values on the given axis and the Y coordinates are the fraction of outputs
+// less than or equal to them.
+func (set *ValuesSet) ECDFOn(axis Axis) (plotter.XYs, error) {
+	_, outputs, err := set.scalars()
+	if err != nil {
+		return nil, err
+	}
+	setValuesOn(axis, outputs)
+	return ecdfPoints(outputs, axis), nil
+}
+
+// An ECDFPlot plots the empirical cumulative distribution function (ECDF) of the
+// output values of one or more functions. It's a robust way to compare the
+// distributions of the outputs of different implementations.
+type ECDFPlot struct {
+	Title string
+	// X converts the output values to points on the X axis.
+	X Axis
+	// Series are the functions whose output distributions are plotted. Each
+	// series is drawn in a different color and named in the plot legend.
+	Series []Series
+	// Theme sets the background, grid, series colors, and fonts of the plot.
+	Theme Theme
+	// Width and Height are the size of the plot image. If zero, the image is
+	// 20 inches wide and 4 inches tall.
+	Width, Height vg.Length
+}
+
+// AddSeries adds a named function whose output distribution is plotted.
+func (ep *ECDFPlot) AddSeries(name string, fn Fn) {
+	ep.Series = append(ep.Series, Series{Name: name, Fn: fn})
+}
+
+// Save writes the ECDF plot as an image to the given filename. The image format
+// is determined by the file extension.
+func (ep ECDFPlot) Save(filename string) error {
+	if ep.X == nil {
+		return errors.New("ECDF plot has no X axis")
+	}
+	outputs := make([][]*big.Float, len(ep.Series))
+	var allOutputs []*big.Float
+	for i := range ep.Series {
+		var err error
+		_, outputs[i], err = ep.Series[i].Fn.ValuesSet().scalars()
+		if err != nil {
+			return errors.WithMessage(err, "error converting values of series "+ep.Series[i].Name)
+		}
+		allOutputs = append(allOutputs, outputs[i]...)
+	}
+	if len(allOutputs) == 0 {
+		return errors.WithMessage(ErrNoSamples, "ECDF plot has no samples to draw")
+	}
+	setValuesOn(ep.X, allOutputs)
+
+	p, err := plot.New()
+	if err != nil {
+		return withKind(ErrRender, errors.WithMessage(err, "error creating plot"))
+	}
+	if err := ep.Theme.apply(p); err != nil {
+		return withKind(ErrRender, err)
+	}
+	p.Title.Text = ep.Title
+	p.X.Label.Text = " "
+	p.Y.Label.Text = " "
+	p.X.Tick.Marker = tickerFor(ep.X, nil)
+	p.Y.Min, p.Y.Max = 0, 1
+
+	styles := seriesStyles(ep.Theme, ep.Series)
+	for i := range ep.Series {
+		if len(outputs[i]) == 0 {
+			continue
+		}
+		line, err := plotter.NewLine(ecdfPoints(outputs[i], ep.X))
+		if err == plotter.ErrInfinity {
+			return withKind(ErrRender, errors.New("infinity value found, consider using an axis that supports scaling"))
+		} else if err != nil {
+			return withKind(ErrRender, errors.WithMessage(err, "error creating ECDF line for series "+ep.Series[i].Name))
+		}
+		line.StepStyle = plotter.PostStep
+		line.Color = styles[i].Color
+		if ep.Series[i].Style.Dashes != nil {
+			line.Dashes = styles[i].Dashes
+		}
+		p.Add(line)
+		p.Legend.Add(ep.Series[i].Name, line)
+	}
+
+	// Save the plot to a file. The format is determined by the file extension.
+	width, height := plotSize(ep.Width, ep.Height)
+	err = p.Save(width, height, filename)
+	return withKind(ErrRender, errors.WithMessage(err, "error writing plot image"))
+}
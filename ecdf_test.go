@@ -0,0 +1,94 @@
+package fnplot
+
+import (
+	"image/png"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+func TestECDFPoints(t *testing.T) {
+	tests := []struct {
+		description string
+		values      []float64
+		expected    plotter.XYs
+	}{
+		{
+			description: "empty",
+			values:      nil,
+			expected:    nil,
+		},
+		{
+			description: "single value",
+			values:      []float64{5},
+			expected:    plotter.XYs{{X: 5, Y: 0}, {X: 5, Y: 1}},
+		},
+		{
+			description: "unsorted",
+			values:      []float64{3, 1, 4, 2},
+			expected:    plotter.XYs{{X: 1, Y: 0}, {X: 1, Y: 0.25}, {X: 2, Y: 0.5}, {X: 3, Y: 0.75}, {X: 4, Y: 1}},
+		},
+		{
+			description: "duplicates",
+			values:      []float64{2, 1, 2},
+			expected:    plotter.XYs{{X: 1, Y: 0}, {X: 1, Y: 1.0 / 3}, {X: 2, Y: 2.0 / 3}, {X: 2, Y: 1}},
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			values := make([]*big.Float, len(test.values))
+			for i, v := range test.values {
+				values[i] = big.NewFloat(v)
+			}
+			points := ecdfPoints(values, &StdAxix{})
+			require.Len(t, points, len(test.expected), "Expected a point for each value and the starting point")
+			for i := range test.expected {
+				assert.Equal(t, test.expected[i].X, points[i].X, "Expected and actual X of point %d are different", i)
+				assert.InDelta(t, test.expected[i].Y, points[i].Y, 1e-12, "Expected and actual Y of point %d are different", i)
+			}
+		})
+	}
+}
+
+func TestECDFOn(t *testing.T) {
+	set := newTestSet(t, []float64{1, 2, 3}, []float64{30, 10, 20})
+	points, err := set.ECDFOn(&StdAxix{})
+	require.NoError(t, err, "Error getting ECDF points")
+	assert.Equal(t, plotter.XYs{{X: 10, Y: 0}, {X: 10, Y: 1.0 / 3}, {X: 20, Y: 2.0 / 3}, {X: 30, Y: 1}}, points,
+		"Expected the ECDF of the outputs")
+}
+
+func TestECDFPlotSave(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fnplot")
+	require.NoError(t, err, "Error creating temporary directory")
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "ecdf.png")
+
+	ep := ECDFPlot{Title: "Outputs", X: &StdAxix{}, Width: 4 * vg.Inch, Height: 2 * vg.Inch}
+	ep.AddSeries("a", FnOf(newTestSet(t, []float64{1, 2, 3}, []float64{3, 1, 2})))
+	ep.AddSeries("b", FnOf(newTestSet(t, []float64{1, 2}, []float64{5, 5})))
+	require.NoError(t, ep.Save(filename), "Error saving ECDF plot")
+
+	f, err := os.Open(filename)
+	require.NoError(t, err, "Error opening ECDF plot image")
+	defer f.Close()
+	config, err := png.DecodeConfig(f)
+	require.NoError(t, err, "Error decoding ECDF plot image")
+	assert.Equal(t, int(vg.Length(4*vg.Inch).Dots(96)), config.Width, "Expected the width of the image")
+	assert.Equal(t, int(vg.Length(2*vg.Inch).Dots(96)), config.Height, "Expected the height of the image")
+
+	ep.X = nil
+	assert.Error(t, ep.Save(filename), "Expected an error saving an ECDF plot without an X axis")
+	empty := ECDFPlot{X: &StdAxix{}}
+	empty.AddSeries("empty", FnOf(NewValuesSet(nil)))
+	assert.Equal(t, ErrNoSamples, errors.Cause(empty.Save(filename)), "Expected an error saving an ECDF plot without samples")
+}
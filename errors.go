@@ -0,0 +1,48 @@
+package fnplot
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Errors returned by fnplot. The errors returned for these failures are
+// wrapped with more detail, so compare errors.Cause(err) with them.
+var (
+	// ErrNoSamples is returned for plots and series without samples.
+	ErrNoSamples = errors.New("no samples")
+	// ErrScalarConversion is returned when input or output values can't be
+	// converted to scalar values.
+	ErrScalarConversion = errors.New("error converting values to scalar values")
+	// ErrRender is returned when a plot can't be drawn or written as an
+	// image.
+	ErrRender = errors.New("error rendering plot")
+)
+
+// kindError is an error of one of the fnplot errors. Its message is the
+// message of the error, and its cause is the fnplot error.
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+// Cause returns the fnplot error, for errors.Cause.
+func (e *kindError) Cause() error {
+	return e.kind
+}
+
+// Unwrap returns the fnplot error, for errors.Is.
+func (e *kindError) Unwrap() error {
+	return e.kind
+}
+
+// withKind returns err as an error of the given fnplot error, or nil if err is
+// nil.
+func withKind(kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &kindError{kind: kind, err: err}
+}
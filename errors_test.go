@@ -0,0 +1,121 @@
+package fnplot
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingWriter is an io.Writer that fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("failing writer")
+}
+
+func TestErrors(t *testing.T) {
+	ints := NewValuesSet(nil)
+	require.NoError(t, ints.insert(NewValues(1), NewValues(2)), "Error inserting values")
+
+	tests := []struct {
+		description string
+		pl          Plot
+		writeErr    bool
+		expected    error
+	}{
+		{
+			description: "no samples",
+			pl:          Plot{Fn: FnOf(NewValuesSet(nil)), X: &StdAxix{}, Y: &StdAxix{}},
+			expected:    ErrNoSamples,
+		},
+		{
+			description: "scalar conversion",
+			pl:          Plot{Fn: FnOf(ints), X: &StdAxix{}, Y: &StdAxix{}, Scalars: ScalarConfig{Output: ByLength}},
+			expected:    ErrScalarConversion,
+		},
+		{
+			description: "render",
+			pl:          Plot{Fn: FnOf(ints), X: &StdAxix{}, Y: &StdAxix{}},
+			writeErr:    true,
+			expected:    ErrRender,
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			var err error
+			if test.writeErr {
+				err = test.pl.Write(failingWriter{}, "svg")
+			} else {
+				err = test.pl.Write(ioutil.Discard, "svg")
+			}
+			require.Error(t, err, "Expected an error writing the plot")
+			assert.Equal(t, test.expected, errors.Cause(err), "Expected the cause of the error")
+		})
+	}
+}
+
+func TestSaveErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fnplot")
+	require.NoError(t, err, "Error creating temporary directory")
+	defer os.RemoveAll(dir)
+	png := filepath.Join(dir, "plot.png")
+	unwritable := filepath.Join(dir, "missing", "plot.png")
+
+	oneArg := newTestSet(t, []float64{1, 2}, []float64{1, 4})
+	twoArgs := NewValuesSet(nil)
+	require.NoError(t, twoArgs.insert(NewValues(1, 2), NewValues(3)), "Error inserting values")
+	ecdf := func(set *ValuesSet) ECDFPlot {
+		ep := ECDFPlot{X: &StdAxix{}}
+		ep.AddSeries("series", FnOf(set))
+		return ep
+	}
+	heatmap := func(set *ValuesSet) HeatmapPlot {
+		return HeatmapPlot{Fn: FnOf(set), X: &StdAxix{}, Y: &StdAxix{}}
+	}
+
+	tests := []struct {
+		description string
+		save        func() error
+		expected    error
+	}{
+		{
+			description: "ECDF no samples",
+			save:        func() error { return ecdf(NewValuesSet(nil)).Save(png) },
+			expected:    ErrNoSamples,
+		},
+		{
+			description: "ECDF render",
+			save:        func() error { return ecdf(oneArg).Save(unwritable) },
+			expected:    ErrRender,
+		},
+		{
+			description: "heatmap no samples",
+			save:        func() error { return heatmap(NewValuesSet(nil)).Save(png) },
+			expected:    ErrNoSamples,
+		},
+		{
+			description: "heatmap scalar conversion",
+			save:        func() error { return heatmap(oneArg).Save(png) },
+			expected:    ErrScalarConversion,
+		},
+		{
+			description: "heatmap render",
+			save:        func() error { return heatmap(twoArgs).Save(unwritable) },
+			expected:    ErrRender,
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			err := test.save()
+			require.Error(t, err, "Expected an error saving the plot")
+			assert.Equal(t, test.expected, errors.Cause(err), "Expected the cause of the error")
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package fnplot
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/plot/plotter"
+)
+
+// A LinearFit is a least-squares linear regression line, y = Slope*x +
+// Intercept, fit to a set of points.
+type LinearFit struct {
+	Slope     float64
+	Intercept float64
+	// RSquared is the coefficient of determination (R²) of the fit. The closer
+	// it is to 1, the better the line fits the points.
+	RSquared float64
+}
+
+// FitLinear fits a least-squares regression line to the points. It returns an
+// error if there are fewer than two points or if all points have the same X
+// value.
+func FitLinear(points plotter.XYer) (LinearFit, error) {
+	n := points.Len()
+	if n < 2 {
+		return LinearFit{}, errors.New("at least 2 points are required to fit a line")
+	}
+
+	var meanX, meanY float64
+	for i := 0; i < n; i++ {
+		x, y := points.XY(i)
+		meanX += x
+		meanY += y
+	}
+	meanX /= float64(n)
+	meanY /= float64(n)
+
+	var sxx, sxy float64
+	for i := 0; i < n; i++ {
+		x, y := points.XY(i)
+		sxx += (x - meanX) * (x - meanX)
+		sxy += (x - meanX) * (y - meanY)
+	}
+	if sxx == 0 {
+		return LinearFit{}, errors.New("cannot fit a line to points with the same X value")
+	}
+
+	fit := LinearFit{Slope: sxy / sxx}
+	fit.Intercept = meanY - fit.Slope*meanX
+	fit.RSquared = rSquared(points, meanY, fit.Y)
+	return fit, nil
+}
+
+// rSquared returns the coefficient of determination of the function f fit to
+// the points, given the mean Y value of the points.
+func rSquared(points plotter.XYer, meanY float64, f func(float64) float64) float64 {
+	var ssRes, ssTot float64
+	for i := 0; i < points.Len(); i++ {
+		x, y := points.XY(i)
+		ssRes += (y - f(x)) * (y - f(x))
+		ssTot += (y - meanY) * (y - meanY)
+	}
+	if ssTot == 0 {
+		if ssRes == 0 {
+			return 1
+		}
+		return 0
+	}
+	return 1 - ssRes/ssTot
+}
+
+// Y returns the Y value of the regression line at x.
+func (lf LinearFit) Y(x float64) float64 {
+	return lf.Slope*x + lf.Intercept
+}
+
+func (lf LinearFit) String() string {
+	return fmt.Sprintf("y = %.4gx + %.4g (R² = %.4f)", lf.Slope, lf.Intercept, lf.RSquared)
+}
+
+// A CurveModel is a family of curves that can be fit to a set of points.
+type CurveModel string
+
+const (
+	// PolynomialModel curves are y = c0 + c1*x + c2*x² + ...
+	PolynomialModel CurveModel = "polynomial"
+	// LogarithmicModel curves are y = c0 + c1*ln(x). Only points with positive
+	// X values can be fit.
+	LogarithmicModel CurveModel = "logarithmic"
+	// ExponentialModel curves are y = c0 * e^(c1*x). Only points with positive
+	// Y values can be fit.
+	ExponentialModel CurveModel = "exponential"
+)
+
+// A CurveFit is a least-squares fit of a curve model to a set of points.
+type CurveFit struct {
+	Model CurveModel
+	// Coefficients are the fitted coefficients of the model, as described by
+	// the CurveModel constants.
+	Coefficients []float64
+	// RSquared is the coefficient of determination (R²) of the fit.
+	RSquared float64
+	// AIC is the Akaike information criterion of the fit, which balances how
+	// well the curve fits the points against the number of coefficients. Lower
+	// is better.
+	AIC float64
+}
+
+// FitPolynomial fits a least-squares polynomial of the given degree to the
+// points.
+func FitPolynomial(points plotter.XYer, degree int) (CurveFit, error) {
+	if degree < 0 {
+		return CurveFit{}, errors.New("polynomial degree must not be negative")
+	}
+	coefficients, err := leastSquares(points, degree+1, func(x float64, row []float64) {
+		xi := 1.0
+		for i := range row {
+			row[i] = xi
+			xi *= x
+		}
+	}, func(y float64) float64 { return y })
+	if err != nil {
+		return CurveFit{}, errors.WithMessage(err, fmt.Sprintf("error fitting degree %d polynomial", degree))
+	}
+	return newCurveFit(points, PolynomialModel, coefficients), nil
+}
+
+// FitLogarithmic fits a least-squares logarithmic curve to the points.
+func FitLogarithmic(points plotter.XYer) (CurveFit, error) {
+	for i := 0; i < points.Len(); i++ {
+		if x, _ := points.XY(i); x <= 0 {
+			return CurveFit{}, errors.New("cannot fit a logarithmic curve to points with X values <= 0")
+		}
+	}
+	coefficients, err := leastSquares(points, 2, func(x float64, row []float64) {
+		row[0] = 1
+		row[1] = math.Log(x)
+	}, func(y float64) float64 { return y })
+	if err != nil {
+		return CurveFit{}, errors.WithMessage(err, "error fitting logarithmic curve")
+	}
+	return newCurveFit(points, LogarithmicModel, coefficients), nil
+}
+
+// FitExponential fits an exponential curve to the points. The curve is fit by
+// least squares to the natural log of the Y values.
+func FitExponential(points plotter.XYer) (CurveFit, error) {
+	for i := 0; i < points.Len(); i++ {
+		if _, y := points.XY(i); y <= 0 {
+			return CurveFit{}, errors.New("cannot fit an exponential curve to points with Y values <= 0")
+		}
+	}
+	coefficients, err := leastSquares(points, 2, func(x float64, row []float64) {
+		row[0] = 1
+		row[1] = x
+	}, math.Log)
+	if err != nil {
+		return CurveFit{}, errors.WithMessage(err, "error fitting exponential curve")
+	}
+	coefficients[0] = math.Exp(coefficients[0])
+	return newCurveFit(points, ExponentialModel, coefficients), nil
+}
+
+// BestFit fits linear, quadratic, cubic, logarithmic, and exponential curves to
+// the points and returns the fit with the lowest AIC. Models that can't be fit
+// to the points are skipped.
+func BestFit(points plotter.XYer) (CurveFit, error) {
+	fits := []func() (CurveFit, error){
+		func() (CurveFit, error) { return FitPolynomial(points, 1) },
+		func() (CurveFit, error) { return FitPolynomial(points, 2) },
+		func() (CurveFit, error) { return FitPolynomial(points, 3) },
+		func() (CurveFit, error) { return FitLogarithmic(points) },
+		func() (CurveFit, error) { return FitExponential(points) },
+	}
+	var best *CurveFit
+	for _, fitFn := range fits {
+		fit, err := fitFn()
+		if err != nil {
+			continue
+		}
+		if best == nil || fit.AIC < best.AIC {
+			best = &fit
+		}
+	}
+	if best == nil {
+		return CurveFit{}, errors.New("no curve model could be fit to the points")
+	}
+	return *best, nil
+}
+
+// leastSquares solves the linear least-squares problem for k coefficients.
+// The row function fills in the k terms of the design matrix for an X value
+// and the transform function converts each Y value before solving.
+func leastSquares(
+	points plotter.XYer,
+	k int,
+	row func(x float64, row []float64),
+	transform func(y float64) float64,
+) ([]float64, error) {
+	n := points.Len()
+	if n < k {
+		return nil, fmt.Errorf("at least %d points are required, got %d", k, n)
+	}
+	a := mat.NewDense(n, k, nil)
+	b := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		x, y := points.XY(i)
+		row(x, a.RawRowView(i))
+		b.SetVec(i, transform(y))
+	}
+
+	var solution mat.VecDense
+	if err := solution.SolveVec(a, b); err != nil {
+		return nil, errors.WithMessage(err, "error solving least squares")
+	}
+	coefficients := make([]float64, k)
+	for i := range coefficients {
+		coefficients[i] = solution.AtVec(i)
+	}
+	return coefficients, nil
+}
+
+// newCurveFit calculates the goodness of fit of the fitted coefficients.
+func newCurveFit(points plotter.XYer, model CurveModel, coefficients []float64) CurveFit {
+	fit := CurveFit{Model: model, Coefficients: coefficients}
+
+	n := points.Len()
+	var meanY, rss float64
+	for i := 0; i < n; i++ {
+		x, y := points.XY(i)
+		meanY += y
+		rss += (y - fit.Y(x)) * (y - fit.Y(x))
+	}
+	meanY /= float64(n)
+	fit.RSquared = rSquared(points, meanY, fit.Y)
+	fit.AIC = float64(n)*math.Log(rss/float64(n)) + 2*float64(len(coefficients))
+	return fit
+}
+
+// Y returns the Y value of the fitted curve at x.
+func (cf CurveFit) Y(x float64) float64 {
+	switch cf.Model {
+	case PolynomialModel:
+		// Evaluate the polynomial using Horner's method.
+		var y float64
+		for i := len(cf.Coefficients) - 1; i >= 0; i-- {
+			y = y*x + cf.Coefficients[i]
+		}
+		return y
+	case LogarithmicModel:
+		return cf.Coefficients[0] + cf.Coefficients[1]*math.Log(x)
+	case ExponentialModel:
+		return cf.Coefficients[0] * math.Exp(cf.Coefficients[1]*x)
+	}
+	return math.NaN()
+}
+
+func (cf CurveFit) String() string {
+	var formula string
+	switch cf.Model {
+	case PolynomialModel:
+		var terms []string
+		for i := len(cf.Coefficients) - 1; i >= 0; i-- {
+			switch i {
+			case 0:
+				terms = append(terms, fmt.Sprintf("%.4g", cf.Coefficients[i]))
+			case 1:
+				terms = append(terms, fmt.Sprintf("%.4gx", cf.Coefficients[i]))
+			default:
+				terms = append(terms, fmt.Sprintf("%.4gx^%d", cf.Coefficients[i], i))
+			}
+		}
+		formula = strings.Join(terms, " + ")
+	case LogarithmicModel:
+		formula = fmt.Sprintf("%.4g + %.4g·ln(x)", cf.Coefficients[0], cf.Coefficients[1])
+	case ExponentialModel:
+		formula = fmt.Sprintf("%.4g·e^(%.4gx)", cf.Coefficients[0], cf.Coefficients[1])
+	}
+	return fmt.Sprintf("%s: y = %s (R² = %.4f)", cf.Model, formula, cf.RSquared)
+}
@@ -0,0 +1,101 @@
+package fnplot
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gonum.org/v1/plot/plotter"
+)
+
+func TestFitLinear(t *testing.T) {
+	tests := []struct {
+		description string
+		points      plotter.XYs
+		expected    LinearFit
+	}{
+		{
+			description: "Exact line",
+			points:      plotter.XYs{{X: 0, Y: 1}, {X: 1, Y: 3}, {X: 2, Y: 5}, {X: 3, Y: 7}},
+			expected:    LinearFit{Slope: 2, Intercept: 1, RSquared: 1},
+		},
+		{
+			description: "Horizontal line",
+			points:      plotter.XYs{{X: 0, Y: 4}, {X: 1, Y: 4}, {X: 2, Y: 4}},
+			expected:    LinearFit{Slope: 0, Intercept: 4, RSquared: 1},
+		},
+		{
+			description: "Noisy line",
+			points:      plotter.XYs{{X: 0, Y: 0}, {X: 1, Y: 2}, {X: 2, Y: 1}, {X: 3, Y: 3}},
+			expected:    LinearFit{Slope: 0.8, Intercept: 0.3, RSquared: 0.64},
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			fit, err := FitLinear(test.points)
+			require.NoError(t, err, "Error fitting line")
+			assert.InDelta(t, test.expected.Slope, fit.Slope, 1e-9, "Expected and actual slopes are different")
+			assert.InDelta(t, test.expected.Intercept, fit.Intercept, 1e-9, "Expected and actual intercepts are different")
+			assert.InDelta(t, test.expected.RSquared, fit.RSquared, 1e-9, "Expected and actual R² are different")
+		})
+	}
+}
+
+func TestFitLinearErrors(t *testing.T) {
+	_, err := FitLinear(plotter.XYs{{X: 1, Y: 1}})
+	assert.Error(t, err, "Expected an error fitting a line to one point")
+
+	_, err = FitLinear(plotter.XYs{{X: 1, Y: 1}, {X: 1, Y: 2}})
+	assert.Error(t, err, "Expected an error fitting a line to points with the same X value")
+}
+
+func TestBestFit(t *testing.T) {
+	curve := func(f func(float64) float64) plotter.XYs {
+		points := make(plotter.XYs, 50)
+		for i := range points {
+			points[i].X = float64(i + 1)
+			points[i].Y = f(points[i].X)
+		}
+		return points
+	}
+	tests := []struct {
+		description  string
+		points       plotter.XYs
+		model        CurveModel
+		coefficients []float64
+	}{
+		{
+			description:  "Quadratic",
+			points:       curve(func(x float64) float64 { return 3*x*x - 2*x + 7 }),
+			model:        PolynomialModel,
+			coefficients: []float64{7, -2, 3},
+		},
+		{
+			description:  "Logarithmic",
+			points:       curve(func(x float64) float64 { return 2 + 5*math.Log(x) }),
+			model:        LogarithmicModel,
+			coefficients: []float64{2, 5},
+		},
+		{
+			description:  "Exponential",
+			points:       curve(func(x float64) float64 { return 0.5 * math.Exp(0.2*x) }),
+			model:        ExponentialModel,
+			coefficients: []float64{0.5, 0.2},
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			fit, err := BestFit(test.points)
+			require.NoError(t, err, "Error fitting curve")
+			assert.Equal(t, test.model, fit.Model, "Expected and actual models are different")
+			require.Len(t, fit.Coefficients, len(test.coefficients), "Expected and actual number of coefficients are different")
+			for i := range test.coefficients {
+				assert.InDelta(t, test.coefficients[i], fit.Coefficients[i], 1e-6, "Expected and actual coefficients are different")
+			}
+			assert.InDelta(t, 1, fit.RSquared, 1e-9, "Expected a perfect fit")
+		})
+	}
+}
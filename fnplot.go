@@ -2,13 +2,13 @@ package fnplot
 
 import (
 	"fmt"
-	"log"
+	"image/color"
+	"io"
 	"math/big"
-	"math/rand"
 	"reflect"
 	"sort"
+	"strings"
 	"sync"
-	"time"
 
 	"github.com/leanovate/gopter"
 	"github.com/pkg/errors"
@@ -28,79 +28,227 @@ func (sp sortablePoints) Len() int           { return len(sp) }
 func (sp sortablePoints) Swap(i, j int)      { sp[i], sp[j] = sp[j], sp[i] }
 func (sp sortablePoints) Less(i, j int) bool { return sp[i].X < sp[j].X }
 
-type ioPair struct {
-	input  Values
-	output Values
+type ValuesSet struct {
+	// store holds the samples of the set. If nil, a MemoryStore is created
+	// when the first sample is inserted.
+	store SampleStore
+	// sampled is the number of samples inserted into the set, including
+	// samples that the store doesn't keep.
+	sampled int
+	// config selects the strategies that convert inserted values to scalar
+	// values.
+	config ScalarConfig
+	// seeds are the seeds of the runs that sampled the set.
+	seeds []int64
+	// runConfig is the config of the last run that sampled the set, which
+	// Replay calls the function with.
+	runConfig RunConfig
+	// failures are the inputs that the function returned an error for or
+	// panicked for in runs that recover panics.
+	failures []Failure
+	// onSample are the callbacks called with each inserted sample.
+	onSample []func(s Sample)
+	mu       sync.RWMutex
 }
 
-type ValuesSet struct {
-	pairs     []ioPair
-	minInput  *big.Float
-	maxInput  *big.Float
-	minOutput *big.Float
-	maxOutput *big.Float
-	mu        sync.RWMutex
+// NewValuesSet creates an empty set that keeps its samples in the given store.
+func NewValuesSet(store SampleStore) *ValuesSet {
+	return &ValuesSet{store: store}
+}
+
+// NewValuesSetWithConfig is like NewValuesSet, but converts the inserted values
+// to scalar values with the strategies of the given config. Use NewFnWithSet
+// to sample a function into the set.
+func NewValuesSetWithConfig(store SampleStore, config ScalarConfig) *ValuesSet {
+	return &ValuesSet{store: store, config: config}
 }
 
 // TODO: Consider using a channel instead of a synchronized slice.
 func (set *ValuesSet) insert(input, output Values) error {
-	set.mu.Lock()
-	defer set.mu.Unlock()
+	return set.insertLabeled(input, output, nil)
+}
 
-	set.pairs = append(set.pairs, ioPair{input: input, output: output})
-	in, err := input.Scalar()
+// insertLabeled inserts a sample with the given labels into the set.
+func (set *ValuesSet) insertLabeled(input, output Values, labels Labels) error {
+	s := Sample{Input: input, Output: output, Labels: labels}
+	in, inErr := set.config.input(input)
+	if inErr == nil {
+		s.InputScalar = in
+	}
+	s.InputErr = inErr
+	out, outErr := set.config.output(output)
+	if outErr == nil {
+		s.OutputScalar = out
+	}
+	s.OutputErr = outErr
+
+	set.mu.Lock()
+	err := set.insertSample(s)
+	onSample := set.onSample
+	set.mu.Unlock()
 	if err != nil {
-		return errors.WithMessage(err, "error converting input to int")
+		return err
 	}
-	if set.minInput == nil || set.minInput.Cmp(in) == 1 {
-		set.minInput = in
+	for _, fn := range onSample {
+		fn(s)
 	}
-	if set.maxInput == nil || set.maxInput.Cmp(in) == -1 {
-		set.maxInput = in
+	if inErr != nil {
+		return withKind(ErrScalarConversion, errors.WithMessage(inErr, "error converting input to int"))
 	}
-	out, err := output.Scalar()
-	if err != nil {
-		return errors.WithMessage(err, "error converting output to int")
+	return withKind(ErrScalarConversion, errors.WithMessage(outErr, "error converting output to int"))
+}
+
+// insertSample inserts the sample into the store of the set. The caller must
+// hold the write lock.
+func (set *ValuesSet) insertSample(s Sample) error {
+	if set.store == nil {
+		set.store = NewMemoryStore(0)
 	}
-	if set.minOutput == nil || set.minOutput.Cmp(out) == 1 {
-		set.minOutput = out
+	set.sampled++
+	return errors.WithMessage(set.store.Insert(s), "error storing sample")
+}
+
+// Sampled returns the number of samples inserted into the set. If the set has
+// a store with a capacity, it can be more than the number of samples kept.
+func (set *ValuesSet) Sampled() int {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return set.sampled
+}
+
+// MinMax returns the smallest and largest input and output scalar values of
+// the samples in the set, or nil if there are none.
+func (set *ValuesSet) MinMax() (minInput, maxInput, minOutput, maxOutput *big.Float) {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	if set.store == nil {
+		return nil, nil, nil, nil
 	}
-	if set.maxOutput == nil || set.maxOutput.Cmp(out) == -1 {
-		set.maxOutput = out
+	return set.store.MinMax()
+}
+
+// samplesLocked returns every sample in the set. The caller must hold the read
+// lock while using the samples and must not modify them.
+func (set *ValuesSet) samplesLocked() ([]Sample, error) {
+	switch store := set.store.(type) {
+	case nil:
+		return nil, nil
+	case *MemoryStore:
+		return store.samples, nil
 	}
-	return nil
+	samples := make([]Sample, 0, set.store.Len())
+	err := set.store.Iterate(func(s Sample) bool {
+		samples = append(samples, s)
+		return true
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error reading samples")
+	}
+	return samples, nil
 }
 
-func (set *ValuesSet) PointsOn(xAxis, yAxis Axis) (plotter.XYs, error) {
+// snapshot returns a copy of every sample in the set, so the samples can be
+// used without holding the lock while the set is sampled.
+func (set *ValuesSet) snapshot() ([]Sample, error) {
 	set.mu.RLock()
 	defer set.mu.RUnlock()
+	samples, err := set.samplesLocked()
+	if err != nil {
+		return nil, err
+	}
+	return append([]Sample(nil), samples...), nil
+}
 
-	xAxis.SetMaxValue(set.maxInput)
-	yAxis.SetMaxValue(set.maxOutput)
+// scalars returns the input and output scalar values of every sample in the
+// set.
+func (set *ValuesSet) scalars() (inputs, outputs []*big.Float, err error) {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	samples, err := set.samplesLocked()
+	if err != nil {
+		return nil, nil, err
+	}
+	return sampleScalars(samples)
+}
 
-	points := make(plotter.XYs, len(set.pairs))
-	for i := range set.pairs {
-		inputScalar, err := set.pairs[i].input.Scalar()
-		if err != nil {
-			return nil, errors.WithMessage(err, fmt.Sprintf("error converting input %d to int", i))
+// sampleScalars returns the input and output scalar values of the samples.
+func sampleScalars(samples []Sample) (inputs, outputs []*big.Float, err error) {
+	inputs = make([]*big.Float, len(samples))
+	outputs = make([]*big.Float, len(samples))
+	for i, s := range samples {
+		inputs[i], outputs[i] = s.InputScalar, s.OutputScalar
+		if inputs[i] == nil {
+			return nil, nil, withKind(ErrScalarConversion,
+				errors.WithMessage(scalarError(s.InputErr), fmt.Sprintf("error converting input %d to int", i)))
 		}
-		points[i].X = xAxis.Point(inputScalar)
+		if outputs[i] == nil {
+			return nil, nil, withKind(ErrScalarConversion,
+				errors.WithMessage(scalarError(s.OutputErr), fmt.Sprintf("error converting output %d to int", i)))
+		}
+	}
+	return inputs, outputs, nil
+}
 
-		outputScalar, err := set.pairs[i].output.Scalar()
-		if err != nil {
-			return nil, errors.WithMessage(err, fmt.Sprintf("error converting output %d to int", i))
+// scalarError returns the error converting values to a scalar value that was
+// stored with a sample, so the values aren't converted again.
+func scalarError(err error) error {
+	if err != nil {
+		return err
+	}
+	return errors.New("no scalar value was stored")
+}
+
+// setValuesOn sets the values, and the smallest and largest of them, on the
+// axis.
+func setValuesOn(axis Axis, values []*big.Float) {
+	axis.SetValues(values)
+	if len(values) == 0 {
+		return
+	}
+	min, max := values[0], values[0]
+	for _, v := range values[1:] {
+		if v.Cmp(min) == -1 {
+			min = v
+		}
+		if v.Cmp(max) == 1 {
+			max = v
 		}
-		points[i].Y = yAxis.Point(outputScalar)
+	}
+	axis.SetMinValue(min)
+	axis.SetMaxValue(max)
+}
+
+// pointsOn converts the input and output scalar values to points on the axes,
+// sorted by X.
+func pointsOn(inputs, outputs []*big.Float, xAxis, yAxis Axis) plotter.XYs {
+	points := make(plotter.XYs, len(inputs))
+	for i := range inputs {
+		points[i].X = xAxis.Point(inputs[i])
+		points[i].Y = yAxis.Point(outputs[i])
 	}
 	sort.Sort(sortablePoints(points))
-	return points, nil
+	return points
+}
+
+func (set *ValuesSet) PointsOn(xAxis, yAxis Axis) (plotter.XYs, error) {
+	inputs, outputs, err := set.scalars()
+	if err != nil {
+		return nil, err
+	}
+	setValuesOn(xAxis, inputs)
+	setValuesOn(yAxis, outputs)
+	return pointsOn(inputs, outputs, xAxis, yAxis), nil
 }
 
 // A Fn is a plottable function that holds the function to plot, the input
 // generators, and the inputs and outputs as scalars.
 type Fn struct {
-	p   gopter.Prop
-	set *ValuesSet
+	p      gopter.Prop
+	fn     reflect.Value
+	gens   []gopter.Gen
+	call   caller
+	repeat int
+	set    *ValuesSet
 }
 
 // errorProp creates a property that will always fail with an error.
@@ -116,10 +264,11 @@ func errorProp(err error) gopter.Prop {
 
 // forAllGens returns a gopter.Prop that will run the provided function with
 // inputs generated by the provided generators. The input/output pairs are
-// inserted into the given ValuesSet.
+// inserted into the given ValuesSet. The function is called with call, repeat
+// times for each generated input.
 // Based on "github.com/leanovate/gopter/prop".ForAllNoShrink:
 // https://github.com/leanovate/gopter/blob/293686f39f478c1a469f003eaf0518d15c7c4509/prop/forall_no_shrink.go#L18
-func forAllGens(vs *ValuesSet, fn interface{}, gens ...gopter.Gen) gopter.Prop {
+func forAllGens(vs *ValuesSet, fn interface{}, call caller, repeat int, gens ...gopter.Gen) gopter.Prop {
 	fnVal := reflect.ValueOf(fn)
 	fnType := fnVal.Type()
 	if fnType.Kind() != reflect.Func {
@@ -145,8 +294,14 @@ func forAllGens(vs *ValuesSet, fn interface{}, gens ...gopter.Gen) gopter.Prop {
 			}
 		}
 
-		results := fnVal.Call(args)
-		vs.insert(args, results)
+		for r := 0; r < repeat; r++ {
+			if failure := vs.sample(call, fnVal, args); failure != nil {
+				return &gopter.PropResult{
+					Status: gopter.PropError,
+					Error:  failure,
+				}
+			}
+		}
 
 		// TODO: Is this necessary?
 		result := &gopter.PropResult{Status: gopter.PropTrue}
@@ -158,72 +313,601 @@ func forAllGens(vs *ValuesSet, fn interface{}, gens ...gopter.Gen) gopter.Prop {
 }
 
 func NewFn(fn interface{}, samples int, gens ...Generator) Fn {
+	f := newFn(fn, NewValuesSet(&MemoryStore{samples: make([]Sample, 0, samples)}), gens...)
+	f.run(samples)
+	return f
+}
+
+// NewFnWithSeed is like NewFn, but the inputs are generated from the given seed
+// by a single worker so that the same seed always produces the same samples.
+func NewFnWithSeed(fn interface{}, samples int, seed int64, gens ...Generator) Fn {
+	f := newFn(fn, NewValuesSet(&MemoryStore{samples: make([]Sample, 0, samples)}), gens...)
+	f.runSeeded(samples, seed, 1)
+	return f
+}
+
+// FnOf creates a Fn that plots the pairs already in the set, like a set loaded
+// with LoadJSON. The Fn has no function to run, so sampling it more fails.
+func FnOf(set *ValuesSet) Fn {
+	return Fn{
+		p:   errorProp(errors.New("Fn has no function to run")),
+		set: set,
+	}
+}
+
+// NewFnWithCapacity is like NewFn, but keeps at most capacity of the sampled
+// input/output pairs in memory. The kept pairs are a uniform random sample of
+// every sampled pair, so the plot stays representative of the function while
+// the memory used stays constant.
+func NewFnWithCapacity(fn interface{}, samples, capacity int, gens ...Generator) Fn {
+	return NewFnWithStore(fn, samples, NewMemoryStore(capacity), gens...)
+}
+
+// NewFnWithStore is like NewFn, but keeps the sampled input/output pairs in the
+// given store, like a DiskStore for runs with more samples than fit in memory.
+func NewFnWithStore(fn interface{}, samples int, store SampleStore, gens ...Generator) Fn {
+	return NewFnWithSet(fn, samples, NewValuesSet(store), gens...)
+}
+
+// NewFnWithSet is like NewFn, but inserts the sampled input/output pairs into
+// the given set, like a set created with NewValuesSetWithConfig.
+func NewFnWithSet(fn interface{}, samples int, set *ValuesSet, gens ...Generator) Fn {
+	f := newFn(fn, set, gens...)
+	f.run(samples)
+	return f
+}
+
+// newFn creates a Fn that hasn't been run yet and inserts its samples into the
+// given set.
+func newFn(fn interface{}, vs *ValuesSet, gens ...Generator) Fn {
 	gopterGens := make([]gopter.Gen, len(gens))
 	for i := range gens {
 		gopterGens[i] = gopter.Gen(gens[i])
 	}
-	vs := &ValuesSet{
-		pairs: make([]ioPair, 10),
+	return Fn{
+		p:      forAllGens(vs, fn, callRecovered, 1, gopterGens...),
+		fn:     reflect.ValueOf(fn),
+		gens:   gopterGens,
+		call:   callRecovered,
+		repeat: 1,
+		set:    vs,
 	}
-	f := Fn{
-		p:   forAllGens(vs, fn, gopterGens...),
-		set: vs,
+}
+
+// withCaller returns a copy of the Fn that calls the function with call.
+func (fn Fn) withCaller(call caller) Fn {
+	if !fn.fn.IsValid() {
+		return fn
 	}
-	f.run(samples)
-	return f
+	fn.call = call
+	fn.p = forAllGens(fn.set, fn.fn.Interface(), call, fn.repeat, fn.gens...)
+	return fn
+}
+
+// withRepeat returns a copy of the Fn that calls the function repeat times for
+// each generated input.
+func (fn Fn) withRepeat(repeat int) Fn {
+	if !fn.fn.IsValid() {
+		return fn
+	}
+	fn.repeat = repeat
+	fn.p = forAllGens(fn.set, fn.fn.Interface(), fn.call, repeat, fn.gens...)
+	return fn
 }
 
 // run runs the function with the set of input generators.
 func (fn Fn) run(samples int) error {
-	res := fn.p.Check(&gopter.TestParameters{
-		MinSuccessfulTests: samples,
-		MaxSize:            samples,
-		Seed:               time.Now().UnixNano(),
-		Rng:                rand.New(gopter.NewLockedSource(time.Now().UnixNano())),
-		Workers:            10, // TODO: Make configurable
-
-		// The following values are irrelevant because we're not discarding any
-		// samples.
-		MaxDiscardRatio: 0,
-		MaxShrinkCount:  0,
-		MinSize:         0,
-	})
+	return fn.RunWithConfig(samples, RunConfig{})
+}
+
+// runSeeded runs the function with the set of input generators, generating the
+// inputs from the given seed with the given number of workers.
+func (fn Fn) runSeeded(samples int, seed int64, workers int) error {
+	return fn.check(RunConfig{Workers: workers}.testParameters(samples, seed))
+}
+
+// check runs the function with the given test parameters, recording the seed
+// of the run.
+func (fn Fn) check(params *gopter.TestParameters) error {
+	fn.set.mu.Lock()
+	fn.set.seeds = append(fn.set.seeds, params.Seed)
+	fn.set.mu.Unlock()
+	res := fn.p.Check(params)
+	// Panics that weren't recovered by the run are raised again so that they
+	// aren't mistaken for run errors.
+	if failure, ok := res.Error.(*Failure); ok {
+		panic(failure.Panic)
+	}
 	return res.Error
 }
 
+// runBatches runs the function of each series samples times in batches of
+// batch samples. After each batch, afterBatch is called with the number of
+// samples run so far.
+func runBatches(series []Series, samples, batch int, afterBatch func(done int) error) error {
+	for done := 0; done < samples; {
+		n := batch
+		if samples-done < n {
+			n = samples - done
+		}
+		for _, s := range series {
+			if err := s.Fn.run(n); err != nil {
+				return errors.WithMessage(err, "error running series "+s.Name)
+			}
+		}
+		done += n
+		if err := afterBatch(done); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (fn Fn) ValuesSet() *ValuesSet {
 	return fn.set
 }
 
+// A PlotStyle determines how the points of a plot are drawn.
+type PlotStyle int
+
+const (
+	// LinePoints draws each point and connects the points with lines. It is the
+	// default plot style.
+	LinePoints PlotStyle = iota
+	// Line connects the points with lines without drawing the points.
+	Line
+	// Scatter draws each point without connecting lines. It is best for noisy
+	// outputs, like hash values, where connecting lines are meaningless.
+	Scatter
+	// BoxPlot groups the points into X bins and draws a box plot of the Y
+	// values in each bin.
+	BoxPlot
+	// PercentileBands groups the points into X bins and draws lines through
+	// the 50th, 95th, and 99th percentile of the Y values in each bin.
+	PercentileBands
+	// Density draws a 2D histogram of the points of all series, colored by
+	// the number of points in each cell. It is best for very dense scatter
+	// plots.
+	Density
+	// ErrorBars groups the points with the same X value, like the repeated
+	// calls of RunConfig.Repeat, and draws a line through the median Y value
+	// of each group with error bars from the smallest to the largest Y value.
+	ErrorBars
+	// Band groups the points with the same X value, like ErrorBars, and draws
+	// a line through the median Y value of each group over a shaded band from
+	// the smallest to the largest Y value.
+	Band
+)
+
+// addPoints adds named sets of points to the plot drawn with the given plot
+// style. Each set of points is drawn with the series style at the same index.
+func addPoints(p *plot.Plot, style PlotStyle, styles []SeriesStyle, names []string, points []plotter.XYs) error {
+	if style != LinePoints && style != Line && style != Scatter {
+		return fmt.Errorf("unknown plot style %d", style)
+	}
+	for i := range points {
+		var thumbnails []plot.Thumbnailer
+		if style == LinePoints || style == Line {
+			line, err := plotter.NewLine(points[i])
+			if err != nil {
+				return err
+			}
+			line.LineStyle = styles[i].lineStyle(line.LineStyle)
+			p.Add(line)
+			thumbnails = append(thumbnails, line)
+		}
+		if style == LinePoints || style == Scatter {
+			scatter, err := plotter.NewScatter(points[i])
+			if err != nil {
+				return err
+			}
+			scatter.GlyphStyle = styles[i].glyphStyle(scatter.GlyphStyle)
+			p.Add(scatter)
+			thumbnails = append(thumbnails, scatter)
+		}
+		if names[i] != "" {
+			p.Legend.Add(names[i], thumbnails...)
+		}
+	}
+	return nil
+}
+
+// A Series is a named function plotted alongside other functions on the same
+// Plot.
+type Series struct {
+	Name string
+	Fn   Fn
+	// Style sets the color, dashes, and point glyph of the series. The zero
+	// SeriesStyle draws each series in a different default style.
+	Style SeriesStyle
+}
+
 type Plot struct {
 	Title string
 	Fn    Fn
 	X, Y  Axis
+	Style PlotStyle
+	// Bins is the number of X buckets used by the BoxPlot, PercentileBands,
+	// and Density styles. If zero, 20 bins are used for BoxPlot and
+	// PercentileBands, and 100 bins are used for Density.
+	Bins int
+	// XLabel and YLabel are the labels of the X and Y axes.
+	XLabel, YLabel string
+	// XTickFormat and YTickFormat format the tick mark labels of the X and Y
+	// axes, like HumanizedTicks or ScientificTicks. If nil, the tick marks are
+	// labeled by the axis if it implements plot.Ticker, or with the default
+	// gonum labels otherwise.
+	XTickFormat, YTickFormat TickFormatter
+	// Series are additional functions to plot on the same axes as Fn. Each
+	// series is drawn in a different color and named in the plot legend.
+	Series []Series
+	// Regression draws a least-squares regression line over the points of
+	// each series and adds its slope, intercept, and R² to the legend.
+	Regression bool
+	// CurveFit draws the best fitting curve (see BestFit) over the points of
+	// each series and adds its formula and R² to the legend.
+	CurveFit bool
+	// Complexity appends the estimated complexity class of each series (see
+	// EstimateComplexity) to the plot title.
+	Complexity bool
+	// Theme sets the background, grid, series colors, and fonts of the plot,
+	// like DarkTheme. The zero Theme keeps the gonum defaults.
+	Theme Theme
+	// FnStyle sets the color, dashes, and point glyph of Fn. Each of the
+	// Series has its own Style.
+	FnStyle SeriesStyle
+	// MaxPoints is the most points drawn for each series by the LinePoints,
+	// Line, and Scatter styles. Series with more points are downsampled with
+	// DownsampleLTTB so that large plots render quickly. If zero, 10000 points
+	// are drawn. If negative, every point is drawn.
+	MaxPoints int
+	// Stats adds a caption below the title with the count, mean, median, and
+	// standard deviation of the outputs of each series.
+	Stats bool
+	// Scalars selects the strategies that convert the input and output values
+	// of every series to the plotted scalar values, like Lexical for string
+	// inputs. If a strategy is nil, the scalar values converted when the series
+	// were sampled are plotted.
+	Scalars ScalarConfig
+	// Seeds adds a caption below the title with the seeds of the runs that
+	// sampled each series, so a surprising plot can be reproduced with
+	// RunWithSeed.
+	Seeds bool
+	// Failures draws the inputs that each series returned an error for or
+	// panicked for (see ValuesSet.Failures) as red crosses, and the inputs that
+	// timed out as orange rings, at an output of zero, or at the bottom of the
+	// plot if zero isn't on the Y axis.
+	Failures bool
+	// Inverse plots the output scalar values of each series on the X axis and
+	// the input scalar values on the Y axis, like for plotting the inverse of
+	// a function. Failures aren't drawn on inverse plots.
+	Inverse bool
+	// Width and Height are the size of the plot images. If zero, images are 20
+	// inches wide and 4 inches tall.
+	Width, Height vg.Length
+
+	// fn, gens, and samples are the function set with WithFunc, sampled by
+	// NewPlot.
+	fn      interface{}
+	gens    []Generator
+	samples int
 }
 
-// Save writes the plot as an image to the given filename. The image format is
-// determined by the file extension.
-func (pl Plot) Save(filename string) error {
+// AddSeries adds a named function to plot on the same axes as the other
+// functions in the plot.
+func (pl *Plot) AddSeries(name string, fn Fn) {
+	pl.Series = append(pl.Series, Series{Name: name, Fn: fn})
+}
+
+// series returns every series to plot, including Fn if it is set.
+func (pl Plot) series() []Series {
+	var series []Series
+	if pl.Fn.set != nil {
+		series = append(series, Series{Name: "Fn", Fn: pl.Fn, Style: pl.FnStyle})
+	}
+	return append(series, pl.Series...)
+}
+
+// seriesScalars returns the input and output scalar values of every series and
+// sets the values of all series on the plot axes so that every series is
+// plotted on the same scale. The values are returned in the same order as the
+// series.
+func (pl Plot) seriesScalars(series []Series) (inputs, outputs [][]*big.Float, err error) {
+	_, inputs, outputs, err = pl.seriesSamples(series)
+	return inputs, outputs, err
+}
+
+// seriesSamples is like seriesScalars, but also returns the samples that the
+// scalar values of each series were taken from, in the same order.
+func (pl Plot) seriesSamples(series []Series) (samples [][]Sample, inputs, outputs [][]*big.Float, err error) {
+	samples = make([][]Sample, len(series))
+	inputs = make([][]*big.Float, len(series))
+	outputs = make([][]*big.Float, len(series))
+	var allInputs, allOutputs []*big.Float
+	for i := range series {
+		set := series[i].Fn.ValuesSet()
+		if !pl.Scalars.isZero() {
+			set, err = set.Rescalar(pl.Scalars.Input, pl.Scalars.Output)
+			if err != nil {
+				return nil, nil, nil, errors.WithMessage(err, "error converting values of series "+series[i].Name)
+			}
+		}
+		if samples[i], err = set.snapshot(); err != nil {
+			return nil, nil, nil, errors.WithMessage(err, "error reading samples of series "+series[i].Name)
+		}
+		inputs[i], outputs[i], err = sampleScalars(samples[i])
+		if err != nil {
+			return nil, nil, nil, errors.WithMessage(err, "error converting values of series "+series[i].Name)
+		}
+		if pl.Inverse {
+			inputs[i], outputs[i] = outputs[i], inputs[i]
+		}
+		allInputs = append(allInputs, inputs[i]...)
+		allOutputs = append(allOutputs, outputs[i]...)
+		if pl.Failures && !pl.Inverse {
+			allInputs = append(allInputs, pl.failureInputs(series[i].Fn.ValuesSet(), anyFailure)...)
+		}
+	}
+	setValuesOn(pl.X, allInputs)
+	setValuesOn(pl.Y, allOutputs)
+	return samples, inputs, outputs, nil
+}
+
+// seriesPoints converts the values of every series to points on the plot axes.
+// The points are returned in the same order as the series.
+func (pl Plot) seriesPoints(series []Series) ([]plotter.XYs, error) {
+	inputs, outputs, err := pl.seriesScalars(series)
+	if err != nil {
+		return nil, err
+	}
+	points := make([]plotter.XYs, len(series))
+	for i := range series {
+		points[i] = pointsOn(inputs[i], outputs[i], pl.X, pl.Y)
+	}
+	return points, nil
+}
+
+// downsample reduces the points of each series to at most MaxPoints points.
+func (pl Plot) downsample(points []plotter.XYs) []plotter.XYs {
+	max := pl.MaxPoints
+	if max == 0 {
+		max = defaultMaxPoints
+	}
+	if max < 0 {
+		return points
+	}
+	downsampled := make([]plotter.XYs, len(points))
+	for i := range points {
+		downsampled[i] = DownsampleLTTB(points[i], max)
+	}
+	return downsampled
+}
+
+// addCurveFit draws the best fitting curve of the points in the given color.
+func addCurveFit(p *plot.Plot, c color.Color, name string, points plotter.XYs) error {
+	fit, err := BestFit(points)
+	if err != nil {
+		return errors.WithMessage(err, "error fitting curve for series "+name)
+	}
+	line := plotter.NewFunction(fit.Y)
+	line.Color = c
+	line.Dashes = plotutil.Dashes(2)
+	p.Add(line)
+	p.Legend.Add(name+": "+fit.String(), line)
+	return nil
+}
+
+// addRegression draws the least-squares regression line of the points in the
+// given color.
+func addRegression(p *plot.Plot, c color.Color, name string, points plotter.XYs) error {
+	fit, err := FitLinear(points)
+	if err != nil {
+		return errors.WithMessage(err, "error fitting regression line for series "+name)
+	}
+	line := plotter.NewFunction(fit.Y)
+	line.Color = c
+	line.Dashes = plotutil.Dashes(1)
+	p.Add(line)
+	p.Legend.Add(name+": "+fit.String(), line)
+	return nil
+}
+
+// complexityTitle returns the plot title annotated with the estimated
+// complexity class of each series.
+func (pl Plot) complexityTitle() (string, error) {
+	series := pl.series()
+	estimates := make([]string, len(series))
+	for i := range series {
+		estimate, err := EstimateComplexity(series[i].Fn.ValuesSet())
+		if err != nil {
+			return "", errors.WithMessage(err, "error estimating complexity of series "+series[i].Name)
+		}
+		estimates[i] = estimate.String()
+		if len(series) > 1 {
+			estimates[i] = series[i].Name + ": " + estimates[i]
+		}
+	}
+	return strings.TrimSpace(pl.Title + " " + strings.Join(estimates, ", ")), nil
+}
+
+// Plot sizes used when writing plot images.
+const (
+	plotWidth  = 20 * vg.Inch
+	plotHeight = 4 * vg.Inch
+)
+
+// render draws the series of the plot on a new gonum plot.
+func (pl Plot) render() (*plot.Plot, error) {
+	series := pl.series()
+	sampled := false
+	for _, s := range series {
+		sampled = sampled || s.Fn.ValuesSet().Len() > 0
+	}
+	if !sampled {
+		return nil, errors.WithMessage(ErrNoSamples, "plot has no samples to draw")
+	}
+
 	p, err := plot.New()
 	if err != nil {
-		return errors.WithMessage(err, "error creating plot")
+		return nil, withKind(ErrRender, errors.WithMessage(err, "error creating plot"))
+	}
+	if err := pl.Theme.apply(p); err != nil {
+		return nil, withKind(ErrRender, err)
 	}
 	p.Title.Text = pl.Title
+	if pl.Complexity {
+		title, err := pl.complexityTitle()
+		if err != nil {
+			return nil, err
+		}
+		p.Title.Text = title
+	}
+	if pl.Stats {
+		caption, err := pl.statsCaption()
+		if err != nil {
+			return nil, err
+		}
+		p.Title.Text = strings.TrimSpace(p.Title.Text + "\n" + caption)
+	}
+	if pl.Seeds {
+		p.Title.Text = strings.TrimSpace(p.Title.Text + "\n" + pl.seedsCaption())
+	}
 	p.X.Label.Text = " "
+	if pl.XLabel != "" {
+		p.X.Label.Text = pl.XLabel
+	}
 	p.Y.Label.Text = " "
+	if pl.YLabel != "" {
+		p.Y.Label.Text = pl.YLabel
+	}
+	p.X.Tick.Marker = tickerFor(pl.X, pl.XTickFormat)
+	p.Y.Tick.Marker = tickerFor(pl.Y, pl.YTickFormat)
 
-	points, err := pl.Fn.ValuesSet().PointsOn(pl.X, pl.Y)
+	points, err := pl.seriesPoints(series)
 	if err != nil {
-		log.Fatalf("Error generating X,Y points: %s", err)
+		return nil, errors.WithMessage(err, "error generating X,Y points")
+	}
+	names := make([]string, len(series))
+	for i := range series {
+		names[i] = series[i].Name
+	}
+	styles := seriesStyles(pl.Theme, series)
+	switch pl.Style {
+	case BoxPlot, PercentileBands:
+		width, _ := pl.size()
+		err = addBinned(p, pl.Style, styles, pl.Bins, names, points, width)
+	case Density:
+		err = addDensity(p, pl.Bins, points)
+	case ErrorBars, Band:
+		err = addRepeats(p, pl.Style, styles, names, points)
+	default:
+		err = addPoints(p, pl.Style, styles, names, pl.downsample(points))
 	}
-	err = plotutil.AddLinePoints(p, "Fn", points)
 	if err == plotter.ErrInfinity {
-		return errors.New("infinity value found, consider using an axis that supports scaling")
+		return nil, withKind(ErrRender, errors.New("infinity value found, consider using an axis that supports scaling"))
 	} else if err != nil {
+		return nil, withKind(ErrRender, err)
+	}
+
+	if pl.Regression {
+		for i := range series {
+			if err := addRegression(p, styles[i].Color, series[i].Name, points[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if pl.CurveFit {
+		for i := range series {
+			if err := addCurveFit(p, styles[i].Color, series[i].Name, points[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if pl.Failures && !pl.Inverse {
+		if err := pl.addFailures(p, series); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// size returns the width and height of the plot images.
+func (pl Plot) size() (width, height vg.Length) {
+	return plotSize(pl.Width, pl.Height)
+}
+
+// plotSize returns the given width and height of a plot image, or the default
+// width and height if they're zero.
+func plotSize(width, height vg.Length) (vg.Length, vg.Length) {
+	if width == 0 {
+		width = plotWidth
+	}
+	if height == 0 {
+		height = plotHeight
+	}
+	return width, height
+}
+
+// Write writes the plot as an image in the given format to w. The supported
+// formats are "eps", "jpg", "jpeg", "pdf", "png", "svg", "tif", and "tiff".
+// Writing draws the samples already in the sets of the plot without running
+// the functions again, so a plot can be written many times.
+func (pl Plot) Write(w io.Writer, format string) error {
+	if err := checkFormat(format); err != nil {
+		return err
+	}
+	p, err := pl.render()
+	if err != nil {
+		return err
+	}
+	width, height := pl.size()
+	writer, err := p.WriterTo(width, height, format)
+	if err != nil {
+		return withKind(ErrRender, errors.WithMessage(err, "error creating plot image writer"))
+	}
+	_, err = writer.WriteTo(w)
+	return withKind(ErrRender, errors.WithMessage(err, "error writing plot image"))
+}
+
+// Save writes the plot as an image to the given filename. The image format is
+// determined by the file extension.
+func (pl Plot) Save(filename string) error {
+	if err := checkExtension(filename); err != nil {
+		return err
+	}
+	p, err := pl.render()
+	if err != nil {
 		return err
 	}
 
 	// Save the plot to a file. The format is determined by the file extension.
-	err = p.Save(20*vg.Inch, 4*vg.Inch, filename)
-	return errors.WithMessage(err, "error writing plot image")
+	width, height := pl.size()
+	err = p.Save(width, height, filename)
+	return withKind(ErrRender, errors.WithMessage(err, "error writing plot image"))
+}
+
+// SaveAll writes the plot as an image in each of the given formats, like "png",
+// "svg", and "pdf", to the filename basePath with the format as its extension.
+// The plot is rendered once and written in every format.
+func (pl Plot) SaveAll(basePath string, formats ...string) error {
+	if len(formats) == 0 {
+		return errors.New("no image formats to save")
+	}
+	for _, format := range formats {
+		if err := checkFormat(format); err != nil {
+			return err
+		}
+	}
+	p, err := pl.render()
+	if err != nil {
+		return err
+	}
+	width, height := pl.size()
+	for _, format := range formats {
+		filename := basePath + "." + format
+		if err := p.Save(width, height, filename); err != nil {
+			return withKind(ErrRender, errors.WithMessage(err, "error writing plot image "+filename))
+		}
+	}
+	return nil
 }
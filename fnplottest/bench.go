@@ -0,0 +1,90 @@
+package fnplottest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matthewdale/fnplot"
+	"github.com/pkg/errors"
+)
+
+// BenchmarkSets are the measurements of a benchmark for a sweep of input
+// sizes. The input of each pair is the size, and the output is the
+// measurement.
+type BenchmarkSets struct {
+	// NsPerOp are the durations of each operation, as time.Duration values.
+	NsPerOp *fnplot.ValuesSet
+	// BytesPerOp are the bytes allocated by each operation, as uint64 values.
+	BytesPerOp *fnplot.ValuesSet
+	// AllocsPerOp are the heap objects allocated by each operation, as uint64
+	// values.
+	AllocsPerOp *fnplot.ValuesSet
+}
+
+// BenchmarkSizes benchmarks fn with testing.Benchmark for each of the sizes and
+// returns the measurements of each benchmark. Plot the measurements with
+// fnplot.FnOf, like:
+//
+//	func TestSortBenchmark(t *testing.T) {
+//		if testing.Short() {
+//			t.Skip("Skipping benchmarks in short mode")
+//		}
+//		sets, err := fnplottest.BenchmarkSizes([]int{10, 100, 1000}, func(b *testing.B, size int) {
+//			for i := 0; i < b.N; i++ {
+//				sort.Ints(rand.Perm(size))
+//			}
+//		})
+//		...
+//		pl := fnplot.Plot{Fn: fnplot.FnOf(sets.NsPerOp), ...}
+//	}
+//
+// The measurements are those of the benchmark timer (see
+// testing.BenchmarkResult), so they exclude the time and allocations while the
+// timer is stopped, like setup before b.ResetTimer. Sizes whose benchmark
+// fails or is skipped have no measurements. testing.Benchmark can't run while
+// another benchmark is running, so call BenchmarkSizes from a test or a
+// program rather than from a benchmark.
+func BenchmarkSizes(sizes []int, fn func(b *testing.B, size int)) (BenchmarkSets, error) {
+	ns := fnplot.NewMemoryStore(0)
+	bytes := fnplot.NewMemoryStore(0)
+	allocs := fnplot.NewMemoryStore(0)
+	for _, size := range sizes {
+		size := size // Capture range variable.
+		result := testing.Benchmark(func(b *testing.B) {
+			fn(b, size)
+		})
+		if result.N == 0 {
+			continue
+		}
+		if err := insert(ns, size, time.Duration(result.NsPerOp())); err != nil {
+			return BenchmarkSets{}, err
+		}
+		if err := insert(bytes, size, uint64(result.AllocedBytesPerOp())); err != nil {
+			return BenchmarkSets{}, err
+		}
+		if err := insert(allocs, size, uint64(result.AllocsPerOp())); err != nil {
+			return BenchmarkSets{}, err
+		}
+	}
+	return BenchmarkSets{
+		NsPerOp:     fnplot.NewValuesSet(ns),
+		BytesPerOp:  fnplot.NewValuesSet(bytes),
+		AllocsPerOp: fnplot.NewValuesSet(allocs),
+	}, nil
+}
+
+// insert inserts the size and measurement into the store.
+func insert(store fnplot.SampleStore, size int, measurement interface{}) error {
+	s := fnplot.Sample{
+		Input:  fnplot.NewValues(size),
+		Output: fnplot.NewValues(measurement),
+	}
+	var err error
+	if s.InputScalar, err = s.Input.Scalar(); err != nil {
+		return errors.WithMessage(err, "error converting size")
+	}
+	if s.OutputScalar, err = s.Output.Scalar(); err != nil {
+		return errors.WithMessage(err, "error converting measurement")
+	}
+	return errors.WithMessage(store.Insert(s), "error storing measurement")
+}
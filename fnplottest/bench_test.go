@@ -0,0 +1,69 @@
+package fnplottest
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/matthewdale/fnplot"
+)
+
+var sink []byte
+
+func TestBenchmarkSizes(t *testing.T) {
+	sets, err := BenchmarkSizes([]int{1 << 10, 1 << 14}, func(b *testing.B, size int) {
+		for i := 0; i < b.N; i++ {
+			sink = make([]byte, size)
+		}
+	})
+	if err != nil {
+		t.Fatalf("Error running benchmarks: %s", err)
+	}
+	for name, set := range map[string]*fnplot.ValuesSet{
+		"ns/op":     sets.NsPerOp,
+		"B/op":      sets.BytesPerOp,
+		"allocs/op": sets.AllocsPerOp,
+	} {
+		if set.Len() != 2 {
+			t.Errorf("Expected a %s measurement for each size, got %d", name, set.Len())
+		}
+	}
+	sets.BytesPerOp.Each(func(_, _ fnplot.Values, size, bytes *big.Float) bool {
+		if bytes.Cmp(size) == -1 {
+			t.Errorf("Expected at least %s B/op, got %s", size, bytes)
+		}
+		return true
+	})
+}
+
+func TestBenchmarkSizesTimer(t *testing.T) {
+	sets, err := BenchmarkSizes([]int{1 << 20}, func(b *testing.B, size int) {
+		// The setup is excluded from the measurements by ResetTimer.
+		time.Sleep(20 * time.Millisecond)
+		sink = make([]byte, size)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+		}
+	})
+	if err != nil {
+		t.Fatalf("Error running benchmarks: %s", err)
+	}
+	sets.NsPerOp.Each(func(_, _ fnplot.Values, _, ns *big.Float) bool {
+		if ns.Cmp(big.NewFloat(float64(20*time.Millisecond))) != -1 {
+			t.Errorf("Expected the setup to be excluded from the duration, got %s ns/op", ns)
+		}
+		return true
+	})
+	sets.BytesPerOp.Each(func(_, _ fnplot.Values, _, bytes *big.Float) bool {
+		if bytes.Cmp(big.NewFloat(1<<20)) != -1 {
+			t.Errorf("Expected the setup to be excluded from the allocated bytes, got %s B/op", bytes)
+		}
+		return true
+	})
+	sets.AllocsPerOp.Each(func(_, _ fnplot.Values, _, allocs *big.Float) bool {
+		if allocs.Sign() != 0 {
+			t.Errorf("Expected no allocations, got %s allocs/op", allocs)
+		}
+		return true
+	})
+}
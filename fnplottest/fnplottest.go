@@ -0,0 +1,76 @@
+// Package fnplottest provides helpers for covering fnplot plots with golden
+// file regression tests and for plotting the measurements of benchmarks.
+package fnplottest
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matthewdale/fnplot"
+)
+
+var update = flag.Bool("update", false, "update fnplottest golden files")
+
+// AssertPlotMatches renders the plot as an SVG image and asserts that it
+// matches the golden file at goldenPath. SVG is used because it is rendered
+// deterministically and refers to fonts by name, so golden files don't depend
+// on the fonts installed on the machine running the tests.
+//
+// The functions of the plot must be sampled deterministically, for example
+// with fnplot.NewFnWithSeed.
+//
+// Run the tests with the -update flag to write the rendered plot to the golden
+// file instead of comparing them.
+func AssertPlotMatches(t testing.TB, pl fnplot.Plot, goldenPath string) bool {
+	t.Helper()
+
+	var actual bytes.Buffer
+	if err := pl.Write(&actual, "svg"); err != nil {
+		t.Errorf("Error rendering plot: %s", err)
+		return false
+	}
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0755); err != nil {
+			t.Errorf("Error creating golden file directory: %s", err)
+			return false
+		}
+		if err := ioutil.WriteFile(goldenPath, actual.Bytes(), 0644); err != nil {
+			t.Errorf("Error writing golden file: %s", err)
+			return false
+		}
+		return true
+	}
+
+	expected, err := ioutil.ReadFile(goldenPath)
+	if err != nil {
+		t.Errorf("Error reading golden file (run with -update to create it): %s", err)
+		return false
+	}
+	if bytes.Equal(expected, actual.Bytes()) {
+		return true
+	}
+
+	expectedLines := strings.Split(string(expected), "\n")
+	actualLines := strings.Split(actual.String(), "\n")
+	for i := 0; i < len(expectedLines) || i < len(actualLines); i++ {
+		var e, a string
+		if i < len(expectedLines) {
+			e = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			a = actualLines[i]
+		}
+		if e != a {
+			t.Errorf("Plot does not match golden file %s at line %d (run with -update to update it)\nexpected: %s\nactual:   %s",
+				goldenPath, i+1, e, a)
+			break
+		}
+	}
+	return false
+}
@@ -0,0 +1,18 @@
+package fnplottest
+
+import (
+	"math"
+	"testing"
+
+	"github.com/matthewdale/fnplot"
+)
+
+func TestAssertPlotMatches(t *testing.T) {
+	pl := fnplot.Plot{
+		Title: "math.Sin",
+		Fn:    fnplot.NewFnWithSeed(math.Sin, 200, 1, fnplot.Float64Range(0, 10)),
+		X:     &fnplot.StdAxix{},
+		Y:     &fnplot.StdAxix{},
+	}
+	AssertPlotMatches(t, pl, "testdata/sin.svg")
+}
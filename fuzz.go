@@ -0,0 +1,257 @@
+package fnplot
+
+import (
+	"bufio"
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/leanovate/gopter"
+	"github.com/pkg/errors"
+)
+
+// fuzzHeader is the first line of every file in a Go fuzzing corpus.
+const fuzzHeader = "go test fuzz v1"
+
+// ReadFuzzCorpus reads the entries of a Go fuzzing corpus directory, like
+// testdata/fuzz/FuzzParse, in the order of the file names. Each entry has the
+// values of the arguments of the fuzz target.
+func ReadFuzzCorpus(dir string) ([][]interface{}, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error reading fuzz corpus directory")
+	}
+	var entries [][]interface{}
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, info.Name()))
+		if err != nil {
+			return nil, errors.WithMessage(err, "error reading fuzz corpus file")
+		}
+		entry, err := parseFuzzEntry(b)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error parsing fuzz corpus file "+info.Name())
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// parseFuzzEntry parses the values of a Go fuzzing corpus file.
+func parseFuzzEntry(b []byte) ([]interface{}, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	if !scanner.Scan() || scanner.Text() != fuzzHeader {
+		return nil, errors.New("missing " + strconv.Quote(fuzzHeader) + " header")
+	}
+	var values []interface{}
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		v, err := parseFuzzValue(string(line))
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, errors.WithMessage(scanner.Err(), "error reading fuzz corpus entry")
+}
+
+// parseFuzzValue parses a value of a Go fuzzing corpus file, which is a
+// conversion of a literal to the type of the value, like []byte("abc") or
+// int(-5).
+func parseFuzzValue(s string) (interface{}, error) {
+	expr, err := parser.ParseExpr(s)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error parsing fuzz value "+s)
+	}
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil, errors.New("fuzz value isn't a conversion: " + s)
+	}
+	var typ string
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		typ = fun.Name
+	case *ast.ArrayType:
+		if elt, ok := fun.Elt.(*ast.Ident); ok && fun.Len == nil && elt.Name == "byte" {
+			typ = "[]byte"
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := fun.X.(*ast.Ident); ok && pkg.Name == "math" {
+			typ = "math." + fun.Sel.Name
+		}
+	}
+
+	// Booleans are identifiers, and every other value is a literal, optionally
+	// negated.
+	if typ == "bool" {
+		ident, ok := call.Args[0].(*ast.Ident)
+		if !ok || ident.Name != "true" && ident.Name != "false" {
+			return nil, errors.New("invalid bool fuzz value: " + s)
+		}
+		return ident.Name == "true", nil
+	}
+	arg := call.Args[0]
+	var sign string
+	if unary, ok := arg.(*ast.UnaryExpr); ok && unary.Op == token.SUB {
+		sign, arg = "-", unary.X
+	}
+	lit, ok := arg.(*ast.BasicLit)
+	if !ok {
+		return nil, errors.New("fuzz value isn't a literal: " + s)
+	}
+	text := sign + lit.Value
+	if lit.Kind == token.CHAR {
+		r, _, _, err := strconv.UnquoteChar(lit.Value[1:len(lit.Value)-1], '\'')
+		if err != nil {
+			return nil, errors.WithMessage(err, "error parsing fuzz value "+s)
+		}
+		text = sign + strconv.Itoa(int(r))
+	}
+
+	switch typ {
+	case "[]byte", "string":
+		str, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error parsing fuzz value "+s)
+		}
+		if typ == "string" {
+			return str, nil
+		}
+		return []byte(str), nil
+	case "int", "int8", "int16", "int32", "rune", "int64":
+		bits := map[string]int{"int": 0, "int8": 8, "int16": 16, "int32": 32, "rune": 32, "int64": 64}[typ]
+		i, err := strconv.ParseInt(text, 0, bits)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error parsing fuzz value "+s)
+		}
+		return convertFuzzValue(i, typ), nil
+	case "uint", "uint8", "byte", "uint16", "uint32", "uint64":
+		bits := map[string]int{"uint": 0, "uint8": 8, "byte": 8, "uint16": 16, "uint32": 32, "uint64": 64}[typ]
+		u, err := strconv.ParseUint(text, 0, bits)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error parsing fuzz value "+s)
+		}
+		return convertFuzzValue(u, typ), nil
+	case "float32", "float64":
+		bits := map[string]int{"float32": 32, "float64": 64}[typ]
+		f, err := strconv.ParseFloat(text, bits)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error parsing fuzz value "+s)
+		}
+		return convertFuzzValue(f, typ), nil
+	case "math.Float32frombits", "math.Float64frombits":
+		bits := map[string]int{"math.Float32frombits": 32, "math.Float64frombits": 64}[typ]
+		u, err := strconv.ParseUint(text, 0, bits)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error parsing fuzz value "+s)
+		}
+		if bits == 32 {
+			return math.Float32frombits(uint32(u)), nil
+		}
+		return math.Float64frombits(u), nil
+	}
+	return nil, errors.New("unsupported fuzz value type: " + s)
+}
+
+// fuzzTypes are the types of the fuzz values by their name in corpus files.
+var fuzzTypes = map[string]reflect.Type{
+	"int":     reflect.TypeOf(int(0)),
+	"int8":    reflect.TypeOf(int8(0)),
+	"int16":   reflect.TypeOf(int16(0)),
+	"int32":   reflect.TypeOf(int32(0)),
+	"rune":    reflect.TypeOf(rune(0)),
+	"int64":   reflect.TypeOf(int64(0)),
+	"uint":    reflect.TypeOf(uint(0)),
+	"uint8":   reflect.TypeOf(uint8(0)),
+	"byte":    reflect.TypeOf(byte(0)),
+	"uint16":  reflect.TypeOf(uint16(0)),
+	"uint32":  reflect.TypeOf(uint32(0)),
+	"uint64":  reflect.TypeOf(uint64(0)),
+	"float32": reflect.TypeOf(float32(0)),
+	"float64": reflect.TypeOf(float64(0)),
+}
+
+// convertFuzzValue converts a parsed number to the named fuzz value type.
+func convertFuzzValue(v interface{}, typ string) interface{} {
+	return reflect.ValueOf(v).Convert(fuzzTypes[typ]).Interface()
+}
+
+// FromFuzzCorpus returns a generator for each argument of the fuzz target of
+// a Go fuzzing corpus directory, like testdata/fuzz/FuzzParse, that replay the
+// corpus entries in the given replay mode. Use all of the generators, in
+// order, as the generators of a function with the same arguments as the fuzz
+// target, so that each sample gets the values of a single entry.
+func FromFuzzCorpus(dir string, mode ReplayMode) ([]Generator, error) {
+	entries, err := ReadFuzzCorpus(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("fuzz corpus directory " + dir + " has no entries")
+	}
+	n := len(entries[0])
+	for _, entry := range entries {
+		if len(entry) != n {
+			return nil, errors.New("fuzz corpus entries have different numbers of values")
+		}
+		for i := range entry {
+			if reflect.TypeOf(entry[i]) != reflect.TypeOf(entries[0][i]) {
+				return nil, errors.New("fuzz corpus entries have different types of values")
+			}
+		}
+	}
+
+	// The first generator picks the entry of each sample with a generator of
+	// entry indexes, and the other generators use the same entry. The
+	// generators of a sample are all called with the same parameters, so the
+	// picked entries are keyed by them.
+	indexes := make([]interface{}, len(entries))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	pick := gopter.Gen(FromSamples(mode, indexes...))
+	var mu sync.Mutex
+	picked := make(map[*gopter.GenParameters]int)
+
+	gens := make([]Generator, n)
+	for arg := range gens {
+		arg := arg // Capture range variable.
+		gens[arg] = func(genParams *gopter.GenParameters) *gopter.GenResult {
+			var index int
+			if arg == 0 {
+				v, ok := pick(genParams).Retrieve()
+				if !ok {
+					return gopter.NewEmptyResult(reflect.TypeOf(entries[0][arg]))
+				}
+				index = v.(int)
+			}
+			if n > 1 {
+				mu.Lock()
+				switch arg {
+				case 0:
+					picked[genParams] = index
+				case n - 1:
+					index = picked[genParams]
+					delete(picked, genParams)
+				default:
+					index = picked[genParams]
+				}
+				mu.Unlock()
+			}
+			return gopter.NewGenResult(entries[index][arg], gopter.NoShrinker)
+		}
+	}
+	return gens, nil
+}
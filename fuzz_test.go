@@ -0,0 +1,84 @@
+package fnplot
+
+import (
+	"io/ioutil"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFuzzValue(t *testing.T) {
+	tests := []struct {
+		value       string
+		expected    interface{}
+		expectedErr bool
+	}{
+		{value: `[]byte("a\x00b")`, expected: []byte("a\x00b")},
+		{value: `string("abc")`, expected: "abc"},
+		{value: `bool(true)`, expected: true},
+		{value: `int(-5)`, expected: -5},
+		{value: `int8(127)`, expected: int8(127)},
+		{value: `int64(0x10)`, expected: int64(16)},
+		{value: `rune('a')`, expected: 'a'},
+		{value: `byte('\x01')`, expected: byte(1)},
+		{value: `uint32(7)`, expected: uint32(7)},
+		{value: `float64(-1.5)`, expected: -1.5},
+		{value: `float32(2)`, expected: float32(2)},
+		{value: `math.Float64frombits(0x3ff0000000000000)`, expected: float64(1)},
+		{value: `int8(128)`, expectedErr: true},
+		{value: `complex128(1)`, expectedErr: true},
+		{value: `"abc"`, expectedErr: true},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.value, func(t *testing.T) {
+			v, err := parseFuzzValue(test.value)
+			if test.expectedErr {
+				assert.Error(t, err, "Expected an error parsing fuzz value")
+				return
+			}
+			require.NoError(t, err, "Error parsing fuzz value")
+			assert.Equal(t, test.expected, v, "Expected and actual values are different")
+		})
+	}
+	v, err := parseFuzzValue(`math.Float64frombits(0x7ff8000000000001)`)
+	require.NoError(t, err, "Error parsing fuzz value")
+	assert.True(t, math.IsNaN(v.(float64)), "Expected NaN")
+}
+
+func TestFromFuzzCorpus(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fnplot")
+	require.NoError(t, err, "Error creating corpus directory")
+	defer os.RemoveAll(dir)
+	entries := map[string]string{
+		"a": "go test fuzz v1\nstring(\"a\")\nint(1)\n",
+		"b": "go test fuzz v1\nstring(\"bb\")\nint(2)\n",
+		"c": "go test fuzz v1\nstring(\"ccc\")\nint(3)\n",
+	}
+	for name, entry := range entries {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(entry), 0644), "Error writing corpus file")
+	}
+
+	gens, err := FromFuzzCorpus(dir, ReplayOnce)
+	require.NoError(t, err, "Error reading fuzz corpus")
+	require.Len(t, gens, 2, "Expected a generator for each argument")
+
+	fn := NewFn(func(s string, i int) bool { return len(s) == i }, 10, gens...)
+	var sampled int
+	err = fn.ValuesSet().Each(func(input, output Values, inScalar, outScalar *big.Float) bool {
+		sampled++
+		assert.Equal(t, "true", output.String(), "Expected the values of a single entry in %s", input)
+		return true
+	})
+	require.NoError(t, err, "Error iterating over samples")
+	assert.Equal(t, 3, sampled, "Expected each entry to be sampled once")
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "d"), []byte("string(\"d\")\n"), 0644), "Error writing corpus file")
+	_, err = FromFuzzCorpus(dir, ReplayOnce)
+	assert.Error(t, err, "Expected an error reading a file without a header")
+}
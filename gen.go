@@ -1,14 +1,165 @@
 package fnplot
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync/atomic"
+	"time"
 	"unicode"
 
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
+	"github.com/pkg/errors"
 )
 
 type Generator gopter.Gen
 
+// suchThatRetries is the most values SuchThat generates to find a value that
+// satisfies its condition.
+const suchThatRetries = 100
+
+// Map returns a generator that generates the values of g mapped with f, which
+// must be a function with one parameter of the type of the generated values
+// and one return value, like func(s []int) []int { sort.Ints(s); return s }.
+func (g Generator) Map(f interface{}) Generator {
+	return Generator(gopter.Gen(g).Map(f))
+}
+
+// FlatMap returns a generator that generates values with the generator that f
+// returns for each value of g. The values generated by the returned generators
+// must have type resultType.
+func (g Generator) FlatMap(f func(interface{}) Generator, resultType reflect.Type) Generator {
+	return Generator(gopter.Gen(g).FlatMap(func(v interface{}) gopter.Gen {
+		return gopter.Gen(f(v))
+	}, resultType))
+}
+
+// SuchThat returns a generator that only generates the values of g that satisfy
+// f, which must be a function with one parameter of the type of the generated
+// values that returns a bool, like func(s string) bool { return s != "" }.
+// Values are generated until one satisfies f, up to 100 times, after which no
+// value is generated, so f should be satisfied by most values.
+func (g Generator) SuchThat(f interface{}) Generator {
+	return Generator(gen.RetryUntil(gopter.Gen(g), f, suchThatRetries))
+}
+
+// WithLabel returns a generator that labels the values of g, which names the
+// values in gopter reports.
+func (g Generator) WithLabel(label string) Generator {
+	return Generator(gopter.Gen(g).WithLabel(label))
+}
+
+// Integer generators.
+// ===================
+
+func IntRange(min, max int) Generator {
+	return Generator(gen.IntRange(min, max))
+}
+
+func Int() Generator {
+	return Generator(gen.Int())
+}
+
+func Int8Range(min, max int8) Generator {
+	return Generator(gen.Int8Range(min, max))
+}
+
+func Int8() Generator {
+	return Generator(gen.Int8())
+}
+
+func Int16Range(min, max int16) Generator {
+	return Generator(gen.Int16Range(min, max))
+}
+
+func Int16() Generator {
+	return Generator(gen.Int16())
+}
+
+func Int32Range(min, max int32) Generator {
+	return Generator(gen.Int32Range(min, max))
+}
+
+func Int32() Generator {
+	return Generator(gen.Int32())
+}
+
+func Int64Range(min, max int64) Generator {
+	return Generator(gen.Int64Range(min, max))
+}
+
+func Int64() Generator {
+	return Generator(gen.Int64())
+}
+
+func UIntRange(min, max uint) Generator {
+	return Generator(gen.UIntRange(min, max))
+}
+
+func UInt() Generator {
+	return Generator(gen.UInt())
+}
+
+func UInt8Range(min, max uint8) Generator {
+	return Generator(gen.UInt8Range(min, max))
+}
+
+func UInt8() Generator {
+	return Generator(gen.UInt8())
+}
+
+func UInt16Range(min, max uint16) Generator {
+	return Generator(gen.UInt16Range(min, max))
+}
+
+func UInt16() Generator {
+	return Generator(gen.UInt16())
+}
+
+func UInt32Range(min, max uint32) Generator {
+	return Generator(gen.UInt32Range(min, max))
+}
+
+func UInt32() Generator {
+	return Generator(gen.UInt32())
+}
+
+func UInt64Range(min, max uint64) Generator {
+	return Generator(gen.UInt64Range(min, max))
+}
+
+func UInt64() Generator {
+	return Generator(gen.UInt64())
+}
+
+// Discrete generators.
+// ====================
+
+func Bool() Generator {
+	return Generator(gen.Bool())
+}
+
+// WeightedBool generates true with probability p (between 0 and 1) and false
+// otherwise.
+func WeightedBool(p float64) Generator {
+	return Generator(func(genParams *gopter.GenParameters) *gopter.GenResult {
+		return gopter.NewGenResult(genParams.Rng.Float64() < p, gopter.NoShrinker)
+	})
+}
+
+// EnumOf generates one of the given values, chosen uniformly at random. The
+// values must all have the same type.
+func EnumOf(values ...interface{}) Generator {
+	return Generator(gen.OneConstOf(values...))
+}
+
 // Floating point generators.
 // ==========================
 
@@ -28,6 +179,81 @@ func Float32() Generator {
 	return Generator(gen.Float32())
 }
 
+// Distribution generators.
+// ========================
+
+// Gaussian generates float64s from a normal distribution with the given mean
+// and standard deviation.
+func Gaussian(mean, stddev float64) Generator {
+	return Generator(func(genParams *gopter.GenParameters) *gopter.GenResult {
+		return gopter.NewGenResult(mean+stddev*genParams.Rng.NormFloat64(), gopter.NoShrinker)
+	})
+}
+
+// Exponential generates float64s from an exponential distribution with the
+// given rate parameter (lambda), which has a mean of 1/rate.
+func Exponential(rate float64) Generator {
+	return Generator(func(genParams *gopter.GenParameters) *gopter.GenResult {
+		return gopter.NewGenResult(genParams.Rng.ExpFloat64()/rate, gopter.NoShrinker)
+	})
+}
+
+// Zipf generates uint64s from 0 to imax (inclusive) from a Zipf distribution,
+// where the probability of k is proportional to (v + k) ** (-s), like the
+// popularity of keys in many workloads. s must be larger than 1 and v must be
+// at least 1.
+func Zipf(s, v float64, imax uint64) Generator {
+	if s <= 1 || v < 1 {
+		return Generator(gen.Fail(reflect.TypeOf(uint64(0))))
+	}
+	return Generator(func(genParams *gopter.GenParameters) *gopter.GenResult {
+		return gopter.NewGenResult(rand.NewZipf(genParams.Rng, s, v, imax).Uint64(), gopter.NoShrinker)
+	})
+}
+
+// LogUniform generates float64s from min to max whose logarithms are uniformly
+// distributed, so every order of magnitude between min and max is sampled
+// equally often. Use LogUniform for plots with a logarithmic X axis, where
+// Float64Range samples mostly the largest order of magnitude. min must be
+// larger than zero.
+func LogUniform(min, max float64) Generator {
+	if min <= 0 || max < min {
+		return Generator(gen.Fail(reflect.TypeOf(float64(0))))
+	}
+	logMin, logMax := math.Log(min), math.Log(max)
+	return Generator(func(genParams *gopter.GenParameters) *gopter.GenResult {
+		v := math.Exp(logMin + (logMax-logMin)*genParams.Rng.Float64())
+		// Keep rounding errors within the range.
+		v = math.Max(min, math.Min(max, v))
+		return gopter.NewGenResult(v, gopter.NoShrinker)
+	})
+}
+
+// Time generators.
+// ================
+
+func Time() Generator {
+	return Generator(gen.Time())
+}
+
+func AnyTime() Generator {
+	return Generator(gen.AnyTime())
+}
+
+// TimeRange generates times from from to to (inclusive).
+func TimeRange(from, to time.Time) Generator {
+	return DurationRange(0, to.Sub(from)).Map(func(d time.Duration) time.Time {
+		return from.Add(d)
+	})
+}
+
+// DurationRange generates durations from min to max (inclusive).
+func DurationRange(min, max time.Duration) Generator {
+	return Generator(gen.Int64Range(int64(min), int64(max))).Map(func(d int64) time.Duration {
+		return time.Duration(d)
+	})
+}
+
 // Rune generators.
 // ================
 
@@ -39,8 +265,45 @@ func Rune() Generator {
 	return Generator(gen.Rune())
 }
 
+// RuneNoControl generates runes that aren't control characters, as defined by
+// unicode.IsControl, including the C1 control characters that
+// gen.RuneNoControl generates.
 func RuneNoControl() Generator {
-	return Generator(RuneNoControl())
+	return Rune().SuchThat(func(r rune) bool { return !unicode.IsControl(r) })
+}
+
+// emojiRanges are the Unicode blocks of most emoji.
+var emojiRanges = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x2600, Hi: 0x26ff, Stride: 1}, // Miscellaneous Symbols
+		{Lo: 0x2700, Hi: 0x27bf, Stride: 1}, // Dingbats
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f300, Hi: 0x1f5ff, Stride: 1}, // Miscellaneous Symbols and Pictographs
+		{Lo: 0x1f600, Hi: 0x1f64f, Stride: 1}, // Emoticons
+		{Lo: 0x1f680, Hi: 0x1f6ff, Stride: 1}, // Transport and Map Symbols
+		{Lo: 0x1f900, Hi: 0x1f9ff, Stride: 1}, // Supplemental Symbols and Pictographs
+	},
+}
+
+// runesToString converts generated rune slices to strings.
+func runesToString(runes []rune) string {
+	return string(runes)
+}
+
+func LetterChar() Generator {
+	return UnicodeChar(unicode.Letter)
+}
+
+// PrintableChar generates runes that are printable, as defined by
+// unicode.IsPrint.
+func PrintableChar() Generator {
+	return Rune().SuchThat(unicode.IsPrint)
+}
+
+// EmojiChar generates runes from the Unicode blocks of most emoji.
+func EmojiChar() Generator {
+	return UnicodeChar(emojiRanges)
 }
 
 // Character generators.
@@ -92,3 +355,438 @@ func Identifier() Generator {
 func UnicodeString(table *unicode.RangeTable) Generator {
 	return Generator(gen.UnicodeString(table))
 }
+
+func LetterString() Generator {
+	return UnicodeString(unicode.Letter)
+}
+
+// PrintableString generates strings of printable runes, as defined by
+// unicode.IsPrint.
+func PrintableString() Generator {
+	return SliceOf(PrintableChar()).Map(runesToString)
+}
+
+// EmojiString generates strings of runes from the Unicode blocks of most emoji.
+func EmojiString() Generator {
+	return UnicodeString(emojiRanges)
+}
+
+// RegexMatch generates strings that match the regular expression, like
+// `[a-z]+@[a-z]+\.com`. If the regular expression is invalid, no strings are
+// generated.
+func RegexMatch(pattern string) Generator {
+	return Generator(gen.RegexMatch(pattern))
+}
+
+// Collection generators.
+// ======================
+
+// withSizeRange returns a generator that generates collections with at least
+// min and at most max elements with the given collection generator.
+func withSizeRange(min, max int, collection gopter.Gen) gopter.Gen {
+	return func(genParams *gopter.GenParameters) *gopter.GenResult {
+		sized := *genParams
+		// MaxSize is exclusive.
+		sized.MinSize, sized.MaxSize = min, max+1
+		return collection(&sized)
+	}
+}
+
+func SliceOf(elem Generator) Generator {
+	return Generator(gen.SliceOf(gopter.Gen(elem)))
+}
+
+func SliceOfN(n int, elem Generator) Generator {
+	return Generator(gen.SliceOfN(n, gopter.Gen(elem)))
+}
+
+// SliceOfRange generates slices with at least min and at most max elements.
+func SliceOfRange(min, max int, elem Generator) Generator {
+	return Generator(withSizeRange(min, max, gen.SliceOf(gopter.Gen(elem))))
+}
+
+// Bytes generates byte slices of random bytes.
+func Bytes() Generator {
+	return SliceOf(UInt8())
+}
+
+// BytesN generates byte slices of n random bytes.
+func BytesN(n int) Generator {
+	return SliceOfN(n, UInt8())
+}
+
+// BytesRange generates byte slices of at least min and at most max random
+// bytes. Plot them with ByLength to plot how a function scales with the size of
+// its input.
+func BytesRange(min, max int) Generator {
+	return SliceOfRange(min, max, UInt8())
+}
+
+func MapOf(key, value Generator) Generator {
+	return Generator(gen.MapOf(gopter.Gen(key), gopter.Gen(value)))
+}
+
+// MapOfRange generates maps with at least min and at most max entries. Maps
+// can have fewer than min entries if the key generator generates duplicate
+// keys.
+func MapOfRange(min, max int, key, value Generator) Generator {
+	return Generator(withSizeRange(min, max, gen.MapOf(gopter.Gen(key), gopter.Gen(value))))
+}
+
+// Struct generators.
+// ==================
+
+// gopterGens converts named generators to gopter generators.
+func gopterGens(gens map[string]Generator) map[string]gopter.Gen {
+	converted := make(map[string]gopter.Gen, len(gens))
+	for name, g := range gens {
+		converted[name] = gopter.Gen(g)
+	}
+	return converted
+}
+
+// StructOf generates values of the struct type rt, generating each field with
+// the generator of the same name in fields. Fields without a generator are
+// left as their zero value.
+func StructOf(rt reflect.Type, fields map[string]Generator) Generator {
+	return Generator(gen.Struct(rt, gopterGens(fields)))
+}
+
+// StructPtrOf is like StructOf, but generates pointers to the structs.
+func StructPtrOf(rt reflect.Type, fields map[string]Generator) Generator {
+	return Generator(gen.StructPtr(rt, gopterGens(fields)))
+}
+
+// PtrOf generates pointers to the values generated by elem, or nil pointers.
+func PtrOf(elem Generator) Generator {
+	return Generator(gen.PtrOf(gopter.Gen(elem)))
+}
+
+// Mixed generators.
+// =================
+
+// OneGenOf generates values with one of the given generators, chosen uniformly
+// at random for each value. The generators must generate values of the same
+// type.
+func OneGenOf(gens ...Generator) Generator {
+	gopterGens := make([]gopter.Gen, len(gens))
+	for i := range gens {
+		gopterGens[i] = gopter.Gen(gens[i])
+	}
+	return Generator(gen.OneGenOf(gopterGens...))
+}
+
+// Frequency generates values with one of the given generators, chosen with a
+// probability proportional to its weight, like tiny inputs nine times as often
+// as huge inputs with map[int]Generator{9: tiny, 1: huge}. The weights must be
+// positive, and the generators must generate values of the same type.
+func Frequency(weighted map[int]Generator) Generator {
+	// Sort the generators by weight so that seeded runs choose the same
+	// generators regardless of the map iteration order.
+	weights := make([]int, 0, len(weighted))
+	for weight := range weighted {
+		weights = append(weights, weight)
+	}
+	sort.Ints(weights)
+	weightedGens := make([]gen.WeightedGen, len(weights))
+	for i, weight := range weights {
+		weightedGens[i] = gen.WeightedGen{Weight: weight, Gen: gopter.Gen(weighted[weight])}
+	}
+	return Generator(gen.Weighted(weightedGens))
+}
+
+// Deterministic generators.
+// =========================
+
+func Const(v interface{}) Generator {
+	return Generator(gen.Const(v))
+}
+
+// counter returns a function that returns 0, 1, 2, ... on successive calls, up
+// to n-1 before starting over at 0. It's safe to call concurrently.
+func counter(n int) func() int {
+	var next uint64
+	return func() int {
+		return int((atomic.AddUint64(&next, 1) - 1) % uint64(n))
+	}
+}
+
+// Sequence generates the ints from start up to stop (inclusive) in increments
+// of step, like 0, 100, ..., 1000000, starting over at start after stop. Unlike
+// IntRange, the inputs cover the range evenly, which gives clean plots of how a
+// function scales. Each generated Sequence starts at start, so create a new one
+// for each function.
+func Sequence(start, stop, step int) Generator {
+	if step <= 0 || stop < start {
+		return Generator(gen.Fail(reflect.TypeOf(0)))
+	}
+	next := counter((stop-start)/step + 1)
+	return Generator(func(*gopter.GenParameters) *gopter.GenResult {
+		return gopter.NewGenResult(start+next()*step, gopter.NoShrinker)
+	})
+}
+
+// Linspace generates n evenly spaced float64s from min to max (inclusive),
+// starting over at min after max. Each generated Linspace starts at min, so
+// create a new one for each function.
+func Linspace(min, max float64, n int) Generator {
+	if n <= 0 {
+		return Generator(gen.Fail(reflect.TypeOf(float64(0))))
+	}
+	next := counter(n)
+	return Generator(func(*gopter.GenParameters) *gopter.GenResult {
+		if n == 1 {
+			return gopter.NewGenResult(min, gopter.NoShrinker)
+		}
+		i := next()
+		return gopter.NewGenResult(min+(max-min)*float64(i)/float64(n-1), gopter.NoShrinker)
+	})
+}
+
+// A BucketScale is how Stratified partitions its range into buckets.
+type BucketScale int
+
+const (
+	// LinearBuckets are buckets of equal width.
+	LinearBuckets BucketScale = iota
+	// LogBuckets are buckets of equal width on a logarithmic scale, like the
+	// decades of a log-scaled axis.
+	LogBuckets
+)
+
+// Stratified generates float64s from min to max that cover the range evenly.
+// The range is partitioned into the given number of buckets, and each generated
+// float64 is drawn uniformly from the next bucket in turn (uniformly on a
+// logarithmic scale for LogBuckets), so every bucket gets at least n/buckets of
+// n generated float64s. Unlike Float64Range, a log-scaled axis has no gaps
+// where few samples fall. LogBuckets need a min more than zero. Each generated
+// Stratified starts at the first bucket, so create a new one for each
+// function.
+func Stratified(min, max float64, buckets int, scale BucketScale) Generator {
+	if buckets <= 0 || max < min || scale == LogBuckets && min <= 0 {
+		return Generator(gen.Fail(reflect.TypeOf(float64(0))))
+	}
+	lo, hi := min, max
+	if scale == LogBuckets {
+		lo, hi = math.Log(min), math.Log(max)
+	}
+	width := (hi - lo) / float64(buckets)
+	next := counter(buckets)
+	return Generator(func(genParams *gopter.GenParameters) *gopter.GenResult {
+		v := lo + width*(float64(next())+genParams.Rng.Float64())
+		if scale == LogBuckets {
+			v = math.Exp(v)
+		}
+		return gopter.NewGenResult(math.Max(min, math.Min(max, v)), gopter.NoShrinker)
+	})
+}
+
+// Exhaustive generates each of the given values exactly once and then stops
+// generating values, so a function sampled more times than there are values is
+// sampled with every value exactly once, giving a complete plot of a small
+// domain instead of a sampled one. To cover every combination of several small
+// domains, generate the combinations as the values of a single argument. Each
+// generated Exhaustive keeps its own position in the values, so create a new one
+// for each Fn.
+func Exhaustive(values ...interface{}) Generator {
+	var resultType reflect.Type
+	if len(values) > 0 {
+		resultType = reflect.TypeOf(values[0])
+	}
+	var next uint64
+	return Generator(func(*gopter.GenParameters) *gopter.GenResult {
+		i := atomic.AddUint64(&next, 1) - 1
+		if i >= uint64(len(values)) {
+			return gopter.NewEmptyResult(resultType)
+		}
+		return gopter.NewGenResult(values[i], gopter.NoShrinker)
+	})
+}
+
+// ExhaustiveRange is like Exhaustive, but generates each int from min to max
+// (inclusive) exactly once.
+func ExhaustiveRange(min, max int) Generator {
+	var values []interface{}
+	for i := min; i <= max; i++ {
+		values = append(values, i)
+	}
+	return Exhaustive(values...)
+}
+
+// Network and identifier generators.
+// ==================================
+
+// IPv4 generates random IPv4 addresses.
+func IPv4() Generator {
+	return BytesN(net.IPv4len).Map(func(b []byte) net.IP {
+		return net.IP(b)
+	})
+}
+
+// IPv6 generates random IPv6 addresses.
+func IPv6() Generator {
+	return BytesN(net.IPv6len).Map(func(b []byte) net.IP {
+		return net.IP(b)
+	})
+}
+
+// MAC generates random 48-bit MAC addresses.
+func MAC() Generator {
+	return BytesN(6).Map(func(b []byte) net.HardwareAddr {
+		return net.HardwareAddr(b)
+	})
+}
+
+// UUID generates random (version 4) UUIDs formatted as strings, like
+// "f47ac10b-58cc-4372-a567-0e02b2c3d479".
+func UUID() Generator {
+	return BytesN(16).Map(func(b []byte) string {
+		b[6] = b[6]&0x0f | 0x40 // Version 4.
+		b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant.
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	})
+}
+
+// URL generates HTTP and HTTPS URLs with a path of up to 3 segments and an
+// optional query, like "https://abc.example.org/x/y?q=z".
+func URL() Generator {
+	return RegexMatch(`^https?://[a-z][a-z0-9]{0,9}(\.[a-z][a-z0-9]{0,9}){0,2}\.(com|org|net|io)(/[a-z0-9_-]{1,8}){0,3}(\?[a-z]{1,5}=[a-z0-9]{1,8})?$`)
+}
+
+// Email generates email-like addresses, like "a.b_c@example.com".
+func Email() Generator {
+	return RegexMatch(`^[a-z0-9]{1,8}([._][a-z0-9]{1,8}){0,2}@[a-z][a-z0-9]{0,9}\.(com|org|net|io)$`)
+}
+
+// JSON generators.
+// ================
+
+// JSONObject generates JSON objects as the map[string]interface{} values that
+// encoding/json decodes objects to. Objects are nested up to maxDepth levels
+// deep, and each object and array has up to maxSize entries.
+func JSONObject(maxDepth, maxSize int) Generator {
+	return Generator(func(genParams *gopter.GenParameters) *gopter.GenResult {
+		return gopter.NewGenResult(jsonObject(genParams.Rng, maxDepth, maxSize), gopter.NoShrinker)
+	})
+}
+
+// JSONDocument is like JSONObject, but generates the objects encoded as JSON
+// documents.
+func JSONDocument(maxDepth, maxSize int) Generator {
+	return JSONObject(maxDepth, maxSize).Map(func(obj map[string]interface{}) []byte {
+		// Encoding the decoded JSON types can't fail.
+		b, _ := json.Marshal(obj)
+		return b
+	})
+}
+
+// jsonObject returns a random JSON object with values nested up to depth
+// levels deep.
+func jsonObject(rng *rand.Rand, depth, maxSize int) map[string]interface{} {
+	n := rng.Intn(maxSize + 1)
+	obj := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		obj[jsonString(rng)] = jsonValue(rng, depth-1, maxSize)
+	}
+	return obj
+}
+
+// jsonValue returns a random JSON value. Arrays and objects are only returned
+// if depth is more than zero.
+func jsonValue(rng *rand.Rand, depth, maxSize int) interface{} {
+	kinds := 4
+	if depth > 0 {
+		kinds = 6
+	}
+	switch rng.Intn(kinds) {
+	case 0:
+		return nil
+	case 1:
+		return rng.Intn(2) == 1
+	case 2:
+		return math.Round(rng.NormFloat64() * 1000)
+	case 3:
+		return jsonString(rng)
+	case 4:
+		arr := make([]interface{}, rng.Intn(maxSize+1))
+		for i := range arr {
+			arr[i] = jsonValue(rng, depth-1, maxSize)
+		}
+		return arr
+	}
+	return jsonObject(rng, depth, maxSize)
+}
+
+// jsonString returns a random string of up to 8 lowercase letters.
+func jsonString(rng *rand.Rand) string {
+	b := make([]byte, rng.Intn(9))
+	for i := range b {
+		b[i] = byte('a' + rng.Intn(26))
+	}
+	return string(b)
+}
+
+// Corpus generators.
+// ==================
+
+// A ReplayMode determines the order that recorded inputs are replayed in.
+type ReplayMode int
+
+const (
+	// ReplayOnce replays each input exactly once, in order, like Exhaustive.
+	ReplayOnce ReplayMode = iota
+	// ReplayCycle replays the inputs in order, starting over after the last
+	// input.
+	ReplayCycle
+	// ReplayRandom replays inputs chosen uniformly at random (sampling with
+	// replacement).
+	ReplayRandom
+)
+
+// FromSamples generates the given values, like real-world inputs recorded
+// from production, in the given replay mode. The values must all have the
+// same type.
+func FromSamples(mode ReplayMode, values ...interface{}) Generator {
+	if len(values) == 0 {
+		return Generator(gen.Fail(nil))
+	}
+	switch mode {
+	case ReplayOnce:
+		return Exhaustive(values...)
+	case ReplayCycle:
+		next := counter(len(values))
+		return Generator(func(*gopter.GenParameters) *gopter.GenResult {
+			return gopter.NewGenResult(values[next()], gopter.NoShrinker)
+		})
+	case ReplayRandom:
+		return Generator(func(genParams *gopter.GenParameters) *gopter.GenResult {
+			return gopter.NewGenResult(values[genParams.Rng.Intn(len(values))], gopter.NoShrinker)
+		})
+	}
+	return Generator(gen.Fail(reflect.TypeOf(values[0])))
+}
+
+// FromCorpus generates the contents of the files in the directory as byte
+// slices in the given replay mode, in the order of the file names.
+// Subdirectories are ignored.
+func FromCorpus(dir string, mode ReplayMode) (Generator, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error reading corpus directory")
+	}
+	var values []interface{}
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, info.Name()))
+		if err != nil {
+			return nil, errors.WithMessage(err, "error reading corpus file")
+		}
+		values = append(values, b)
+	}
+	if len(values) == 0 {
+		return nil, errors.New("corpus directory " + dir + " has no files")
+	}
+	return FromSamples(mode, values...), nil
+}
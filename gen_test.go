@@ -0,0 +1,416 @@
+package fnplot
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/leanovate/gopter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// samples generates n values with the generator.
+func samples(t *testing.T, g Generator, n int) []interface{} {
+	params := gopter.DefaultGenParameters()
+	values := make([]interface{}, n)
+	for i := range values {
+		var ok bool
+		values[i], ok = g(params).Retrieve()
+		require.True(t, ok, "Expected the generator to generate a value")
+	}
+	return values
+}
+
+func TestIntegerGenerators(t *testing.T) {
+	tests := []struct {
+		description string
+		gen         Generator
+		expected    interface{}
+		min, max    float64
+	}{
+		{description: "IntRange", gen: IntRange(-3, 3), expected: int(0), min: -3, max: 3},
+		{description: "Int", gen: Int(), expected: int(0), min: math.MinInt64, max: math.MaxInt64},
+		{description: "Int8Range", gen: Int8Range(-3, 3), expected: int8(0), min: -3, max: 3},
+		{description: "Int8", gen: Int8(), expected: int8(0), min: math.MinInt8, max: math.MaxInt8},
+		{description: "Int16Range", gen: Int16Range(-3, 3), expected: int16(0), min: -3, max: 3},
+		{description: "Int16", gen: Int16(), expected: int16(0), min: math.MinInt16, max: math.MaxInt16},
+		{description: "Int32Range", gen: Int32Range(-3, 3), expected: int32(0), min: -3, max: 3},
+		{description: "Int32", gen: Int32(), expected: int32(0), min: math.MinInt32, max: math.MaxInt32},
+		{description: "Int64Range", gen: Int64Range(-3, 3), expected: int64(0), min: -3, max: 3},
+		{description: "Int64", gen: Int64(), expected: int64(0), min: math.MinInt64, max: math.MaxInt64},
+		{description: "UIntRange", gen: UIntRange(2, 5), expected: uint(0), min: 2, max: 5},
+		{description: "UInt", gen: UInt(), expected: uint(0), min: 0, max: math.MaxUint64},
+		{description: "UInt8Range", gen: UInt8Range(2, 5), expected: uint8(0), min: 2, max: 5},
+		{description: "UInt8", gen: UInt8(), expected: uint8(0), min: 0, max: math.MaxUint8},
+		{description: "UInt16Range", gen: UInt16Range(2, 5), expected: uint16(0), min: 2, max: 5},
+		{description: "UInt16", gen: UInt16(), expected: uint16(0), min: 0, max: math.MaxUint16},
+		{description: "UInt32Range", gen: UInt32Range(2, 5), expected: uint32(0), min: 2, max: 5},
+		{description: "UInt32", gen: UInt32(), expected: uint32(0), min: 0, max: math.MaxUint32},
+		{description: "UInt64Range", gen: UInt64Range(2, 5), expected: uint64(0), min: 2, max: 5},
+		{description: "UInt64", gen: UInt64(), expected: uint64(0), min: 0, max: math.MaxUint64},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			for _, v := range samples(t, test.gen, 100) {
+				require.IsType(t, test.expected, v, "Expected and actual types are different")
+				var f float64
+				switch rv := reflect.ValueOf(v); rv.Kind() {
+				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+					f = float64(rv.Int())
+				default:
+					f = float64(rv.Uint())
+				}
+				assert.True(t, f >= test.min && f <= test.max, "Expected a value between %v and %v, got %v", test.min, test.max, v)
+			}
+		})
+	}
+}
+
+func TestWeightedBool(t *testing.T) {
+	for _, v := range samples(t, WeightedBool(0), 100) {
+		assert.Equal(t, false, v, "Expected only false values")
+	}
+	for _, v := range samples(t, WeightedBool(1), 100) {
+		assert.Equal(t, true, v, "Expected only true values")
+	}
+}
+
+func TestEnumOf(t *testing.T) {
+	for _, v := range samples(t, EnumOf("a", "b"), 100) {
+		assert.Contains(t, []interface{}{"a", "b"}, v, "Expected one of the enum values")
+	}
+}
+
+func TestSliceOfRange(t *testing.T) {
+	lengths := make(map[int]bool)
+	for _, v := range samples(t, SliceOfRange(2, 4, Bool()), 100) {
+		require.IsType(t, []bool{}, v, "Expected a slice of bools")
+		lengths[len(v.([]bool))] = true
+	}
+	assert.Equal(t, map[int]bool{2: true, 3: true, 4: true}, lengths, "Expected every length between 2 and 4")
+}
+
+func TestBytes(t *testing.T) {
+	for _, v := range samples(t, BytesN(5), 10) {
+		require.IsType(t, []byte{}, v, "Expected a byte slice")
+		assert.Len(t, v, 5, "Expected 5 bytes")
+	}
+	for _, v := range samples(t, BytesRange(1, 3), 100) {
+		require.IsType(t, []byte{}, v, "Expected a byte slice")
+		n := len(v.([]byte))
+		assert.True(t, n >= 1 && n <= 3, "Expected between 1 and 3 bytes")
+	}
+}
+
+func TestMapOfRange(t *testing.T) {
+	for _, v := range samples(t, MapOfRange(0, 3, IntRange(0, 1000000), Bool()), 100) {
+		require.IsType(t, map[int]bool{}, v, "Expected a map of ints to bools")
+		assert.True(t, len(v.(map[int]bool)) <= 3, "Expected at most 3 entries")
+	}
+}
+
+func TestStructOf(t *testing.T) {
+	type point struct {
+		X, Y int
+		Name string
+	}
+	fields := map[string]Generator{
+		"X": IntRange(0, 10),
+		"Y": IntRange(-10, 0),
+	}
+	for _, v := range samples(t, StructOf(reflect.TypeOf(point{}), fields), 100) {
+		require.IsType(t, point{}, v, "Expected a point")
+		p := v.(point)
+		assert.True(t, p.X >= 0 && p.X <= 10, "Expected X between 0 and 10")
+		assert.True(t, p.Y >= -10 && p.Y <= 0, "Expected Y between -10 and 0")
+		assert.Equal(t, "", p.Name, "Expected Name without a generator to be empty")
+	}
+	for _, v := range samples(t, StructPtrOf(reflect.TypeOf(point{}), fields), 10) {
+		require.IsType(t, &point{}, v, "Expected a pointer to a point")
+	}
+}
+
+func TestGeneratorCombinators(t *testing.T) {
+	double := IntRange(0, 10).Map(func(i int) int { return 2 * i })
+	for _, v := range samples(t, double, 100) {
+		assert.Equal(t, 0, v.(int)%2, "Expected only even values")
+	}
+
+	nonEmpty := SliceOfRange(0, 2, Bool()).SuchThat(func(s []bool) bool { return len(s) > 0 })
+	for _, v := range samples(t, nonEmpty, 100) {
+		assert.NotEmpty(t, v, "Expected only non-empty slices")
+	}
+
+	sized := IntRange(1, 3).FlatMap(func(v interface{}) Generator {
+		return SliceOfN(v.(int), Bool())
+	}, reflect.TypeOf([]bool{}))
+	for _, v := range samples(t, sized, 100) {
+		n := len(v.([]bool))
+		assert.True(t, n >= 1 && n <= 3, "Expected between 1 and 3 elements")
+	}
+}
+
+func TestFrequency(t *testing.T) {
+	mixed := Frequency(map[int]Generator{
+		9: IntRange(0, 9),
+		1: IntRange(1000, 1009),
+	})
+	var small int
+	for _, v := range samples(t, mixed, 1000) {
+		i := v.(int)
+		require.True(t, i <= 9 || i >= 1000 && i <= 1009, "Expected a value of one of the generators")
+		if i <= 9 {
+			small++
+		}
+	}
+	assert.InDelta(t, 900, small, 100, "Expected about 90% small values")
+
+	for _, v := range samples(t, OneGenOf(IntRange(0, 9), IntRange(1000, 1009)), 100) {
+		i := v.(int)
+		assert.True(t, i <= 9 || i >= 1000 && i <= 1009, "Expected a value of one of the generators")
+	}
+}
+
+func TestSequence(t *testing.T) {
+	assert.Equal(t,
+		[]interface{}{0, 100, 200, 0, 100},
+		samples(t, Sequence(0, 250, 100), 5),
+		"Expected the sequence to start over after stop")
+	assert.Equal(t,
+		[]interface{}{0.0, 0.5, 1.0, 0.0},
+		samples(t, Linspace(0, 1, 3), 4),
+		"Expected evenly spaced values")
+	assert.Equal(t,
+		[]interface{}{"a", "a"},
+		samples(t, Const("a"), 2),
+		"Expected the constant")
+}
+
+func TestStratified(t *testing.T) {
+	tests := []struct {
+		description string
+		min, max    float64
+		scale       BucketScale
+		bucket      func(f float64) int
+	}{
+		{
+			description: "linear",
+			min:         0,
+			max:         100,
+			scale:       LinearBuckets,
+			bucket:      func(f float64) int { return int(f / 25) },
+		},
+		{
+			description: "log",
+			min:         1,
+			max:         10000,
+			scale:       LogBuckets,
+			bucket:      func(f float64) int { return int(math.Log10(f)) },
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			counts := make(map[int]int)
+			for _, v := range samples(t, Stratified(test.min, test.max, 4, test.scale), 40) {
+				f := v.(float64)
+				require.True(t, f >= test.min && f <= test.max, "Expected values in the range, got %v", f)
+				counts[test.bucket(f)]++
+			}
+			assert.Equal(t, map[int]int{0: 10, 1: 10, 2: 10, 3: 10}, counts, "Expected the same number of values in each bucket")
+		})
+	}
+	_, ok := Stratified(0, 1, 4, LogBuckets)(gopter.DefaultGenParameters()).Retrieve()
+	assert.False(t, ok, "Expected no values for a log range from zero")
+}
+
+func TestExhaustive(t *testing.T) {
+	g := Exhaustive("a", "b")
+	assert.Equal(t, []interface{}{"a", "b"}, samples(t, g, 2), "Expected every value in order")
+	_, ok := g(gopter.DefaultGenParameters()).Retrieve()
+	assert.False(t, ok, "Expected no more values")
+
+	fn := NewFn(func(i int) int { return i }, 100, ExhaustiveRange(0, 9))
+	inputs, _ := setFloats(t, fn.ValuesSet())
+	sort.Float64s(inputs)
+	assert.Equal(t,
+		[]float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		inputs,
+		"Expected every input to be sampled exactly once")
+}
+
+func TestDistributions(t *testing.T) {
+	mean := func(values []interface{}) float64 {
+		var sum float64
+		for _, v := range values {
+			switch v := v.(type) {
+			case float64:
+				sum += v
+			case uint64:
+				sum += float64(v)
+			}
+		}
+		return sum / float64(len(values))
+	}
+	assert.InDelta(t, 10, mean(samples(t, Gaussian(10, 2), 1000)), 0.5, "Expected a mean of about 10")
+	assert.InDelta(t, 0.5, mean(samples(t, Exponential(2), 1000)), 0.1, "Expected a mean of about 0.5")
+
+	for _, v := range samples(t, Zipf(2, 1, 100), 100) {
+		assert.True(t, v.(uint64) <= 100, "Expected values up to 100")
+	}
+
+	var small int
+	for _, v := range samples(t, LogUniform(1, 10000), 1000) {
+		f := v.(float64)
+		require.True(t, f >= 1 && f <= 10000, "Expected values between 1 and 10000")
+		if f < 100 {
+			small++
+		}
+	}
+	assert.InDelta(t, 500, small, 100, "Expected about half of the values below 100")
+}
+
+func TestTimeRange(t *testing.T) {
+	from := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	for _, v := range samples(t, TimeRange(from, to), 100) {
+		require.IsType(t, time.Time{}, v, "Expected a time")
+		tm := v.(time.Time)
+		assert.False(t, tm.Before(from) || tm.After(to), "Expected a time between from and to")
+	}
+	for _, v := range samples(t, DurationRange(-time.Second, time.Second), 100) {
+		require.IsType(t, time.Duration(0), v, "Expected a duration")
+		d := v.(time.Duration)
+		assert.True(t, d >= -time.Second && d <= time.Second, "Expected a duration between -1s and 1s")
+	}
+}
+
+func TestRegexMatch(t *testing.T) {
+	re := regexp.MustCompile(`^[a-z]{3}-[0-9]{4}$`)
+	for _, v := range samples(t, RegexMatch(re.String()), 100) {
+		assert.Regexp(t, re, v, "Expected a string matching the regular expression")
+	}
+}
+
+func TestNetworkGenerators(t *testing.T) {
+	for _, v := range samples(t, IPv4(), 10) {
+		require.IsType(t, net.IP{}, v, "Expected an IP")
+		assert.NotNil(t, v.(net.IP).To4(), "Expected an IPv4 address")
+	}
+	for _, v := range samples(t, IPv6(), 10) {
+		require.IsType(t, net.IP{}, v, "Expected an IP")
+		assert.Len(t, v, net.IPv6len, "Expected an IPv6 address")
+	}
+	for _, v := range samples(t, MAC(), 10) {
+		require.IsType(t, net.HardwareAddr{}, v, "Expected a MAC address")
+		assert.Len(t, v, 6, "Expected a 48-bit MAC address")
+	}
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	for _, v := range samples(t, UUID(), 10) {
+		assert.Regexp(t, uuid, v, "Expected a version 4 UUID")
+	}
+	for _, v := range samples(t, URL(), 10) {
+		u, err := url.Parse(v.(string))
+		require.NoError(t, err, "Error parsing URL")
+		assert.Contains(t, []string{"http", "https"}, u.Scheme, "Expected an HTTP or HTTPS URL")
+	}
+	for _, v := range samples(t, Email(), 10) {
+		_, err := mail.ParseAddress(v.(string))
+		assert.NoError(t, err, "Error parsing email address")
+	}
+}
+
+// jsonDepth returns how many levels deep the JSON value is nested.
+func jsonDepth(v interface{}) int {
+	var max int
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, elem := range v {
+			if d := jsonDepth(elem); d > max {
+				max = d
+			}
+		}
+		return max + 1
+	case []interface{}:
+		for _, elem := range v {
+			if d := jsonDepth(elem); d > max {
+				max = d
+			}
+		}
+		return max + 1
+	}
+	return 0
+}
+
+func TestJSONDocument(t *testing.T) {
+	for _, v := range samples(t, JSONDocument(3, 4), 100) {
+		require.IsType(t, []byte{}, v, "Expected a byte slice")
+		var obj map[string]interface{}
+		require.NoError(t, json.Unmarshal(v.([]byte), &obj), "Error decoding JSON document")
+		assert.True(t, len(obj) <= 4, "Expected at most 4 keys")
+		assert.True(t, jsonDepth(obj) <= 3, "Expected at most 3 levels of nesting")
+	}
+}
+
+func TestFromSamples(t *testing.T) {
+	assert.Equal(t,
+		[]interface{}{"a", "b", "a"},
+		samples(t, FromSamples(ReplayCycle, "a", "b"), 3),
+		"Expected the samples to start over after the last sample")
+	for _, v := range samples(t, FromSamples(ReplayRandom, "a", "b"), 100) {
+		assert.Contains(t, []interface{}{"a", "b"}, v, "Expected one of the samples")
+	}
+}
+
+func TestFromCorpus(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fnplot")
+	require.NoError(t, err, "Error creating corpus directory")
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "b"), []byte("second"), 0644), "Error writing corpus file")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a"), []byte("first"), 0644), "Error writing corpus file")
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "c"), 0755), "Error creating subdirectory")
+
+	g, err := FromCorpus(dir, ReplayOnce)
+	require.NoError(t, err, "Error reading corpus")
+	assert.Equal(t,
+		[]interface{}{[]byte("first"), []byte("second")},
+		samples(t, g, 2),
+		"Expected the files in the order of their names")
+
+	_, err = FromCorpus(filepath.Join(dir, "c"), ReplayOnce)
+	assert.Error(t, err, "Expected an error reading an empty corpus")
+}
+
+func TestRuneGenerators(t *testing.T) {
+	for _, v := range samples(t, RuneNoControl(), 100) {
+		assert.False(t, unicode.IsControl(v.(rune)), "Expected no control characters")
+	}
+	for _, v := range samples(t, LetterString(), 100) {
+		for _, r := range v.(string) {
+			assert.True(t, unicode.IsLetter(r), "Expected only letters")
+		}
+	}
+	for _, v := range samples(t, PrintableString(), 100) {
+		for _, r := range v.(string) {
+			assert.True(t, unicode.IsPrint(r), "Expected only printable characters")
+		}
+	}
+	for _, v := range samples(t, EmojiString(), 100) {
+		for _, r := range v.(string) {
+			assert.True(t, unicode.Is(emojiRanges, r), "Expected only emoji")
+		}
+	}
+}
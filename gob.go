@@ -0,0 +1,139 @@
+package fnplot
+
+import (
+	"bufio"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// gobPair is an input/output pair as persisted by WriteGob. The gob encoding of
+// a *big.Float keeps its exact value and precision. A DiskStore also persists
+// the samples whose values couldn't be converted, with the text of the error
+// instead of the scalar value.
+type gobPair struct {
+	Input        *big.Float
+	Output       *big.Float
+	InputValues  string
+	OutputValues string
+	Labels       map[string]string
+	InputErr     string
+	OutputErr    string
+}
+
+// newGobPair returns the pair to persist for the sample.
+func newGobPair(s Sample) gobPair {
+	gp := gobPair{
+		Input:        s.InputScalar,
+		Output:       s.OutputScalar,
+		InputValues:  s.Input.String(),
+		OutputValues: s.Output.String(),
+		Labels:       s.Labels,
+	}
+	if s.InputErr != nil {
+		gp.InputErr = s.InputErr.Error()
+	}
+	if s.OutputErr != nil {
+		gp.OutputErr = s.OutputErr.Error()
+	}
+	return gp
+}
+
+// sample returns the sample that the pair was encoded from, with the formatted
+// values as the input and output values.
+func (gp gobPair) sample() Sample {
+	s := Sample{
+		Input:        NewValues(gp.InputValues),
+		Output:       NewValues(gp.OutputValues),
+		InputScalar:  gp.Input,
+		OutputScalar: gp.Output,
+		Labels:       gp.Labels,
+	}
+	if gp.InputErr != "" {
+		s.InputErr = errors.New(gp.InputErr)
+	}
+	if gp.OutputErr != "" {
+		s.OutputErr = errors.New(gp.OutputErr)
+	}
+	return s
+}
+
+// WriteGob writes the scalar values and the formatted values of every pair in
+// the set as a stream of gob values. It's much smaller and faster to read than
+// JSON for sets with millions of pairs.
+func (set *ValuesSet) WriteGob(w io.Writer) error {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+
+	samples, err := set.samplesLocked()
+	if err != nil {
+		return err
+	}
+	if _, _, err := sampleScalars(samples); err != nil {
+		return err
+	}
+	enc := gob.NewEncoder(w)
+	for i, s := range samples {
+		if err := enc.Encode(newGobPair(s)); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("error encoding pair %d", i))
+		}
+	}
+	return nil
+}
+
+// ReadGob reads a set written by WriteGob, or the file of a closed DiskStore.
+// Like the sets loaded from JSON, each pair keeps its formatted values as
+// strings. The pairs of a DiskStore whose values couldn't be converted keep
+// the conversion error, so converting the read set returns it.
+func ReadGob(r io.Reader) (*ValuesSet, error) {
+	set := &ValuesSet{}
+	dec := gob.NewDecoder(r)
+	for i := 0; ; i++ {
+		var gp gobPair
+		err := dec.Decode(&gp)
+		if err == io.EOF {
+			return set, nil
+		}
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("error decoding pair %d", i))
+		}
+		if (gp.Input == nil && gp.InputErr == "") || (gp.Output == nil && gp.OutputErr == "") {
+			return nil, errors.Errorf("pair %d is missing a scalar value", i)
+		}
+		if err := set.insertSample(gp.sample()); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// SaveGob writes the set with WriteGob to the given filename.
+func (set *ValuesSet) SaveGob(filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return errors.WithMessage(err, "error creating values set file")
+	}
+	w := bufio.NewWriter(f)
+	if err := set.WriteGob(w); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return errors.WithMessage(err, "error writing values set file")
+	}
+	return errors.WithMessage(f.Close(), "error closing values set file")
+}
+
+// LoadGob reads a set written by SaveGob from the given filename.
+func LoadGob(filename string) (*ValuesSet, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error opening values set file")
+	}
+	defer f.Close()
+	return ReadGob(bufio.NewReader(f))
+}
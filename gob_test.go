@@ -0,0 +1,39 @@
+package fnplot
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValuesSetGob(t *testing.T) {
+	set := &ValuesSet{}
+	require.NoError(t, set.insert(NewValues(1.5), NewValues(2.25)), "Error inserting values")
+	require.NoError(t, set.insert(NewValues("a", "b"), NewValues(-3.0)), "Error inserting values")
+
+	var buf bytes.Buffer
+	require.NoError(t, set.WriteGob(&buf), "Error writing gob")
+	loaded, err := ReadGob(&buf)
+	require.NoError(t, err, "Error reading gob")
+
+	expectedInputs, expectedOutputs, err := set.scalars()
+	require.NoError(t, err, "Error converting values")
+	actualInputs, actualOutputs, err := loaded.scalars()
+	require.NoError(t, err, "Error converting loaded values")
+	require.Len(t, actualInputs, len(expectedInputs), "Expected and actual number of pairs are different")
+	for i := range expectedInputs {
+		assert.Zero(t, expectedInputs[i].Cmp(actualInputs[i]), "Expected and actual inputs are different")
+		assert.Zero(t, expectedOutputs[i].Cmp(actualOutputs[i]), "Expected and actual outputs are different")
+	}
+	formattedInputs, _, err := loaded.formatted()
+	require.NoError(t, err, "Error formatting loaded values")
+	assert.Equal(t, []string{"1.5", "a, b"}, formattedInputs, "Expected and actual formatted inputs are different")
+}
+
+func TestReadGobEmpty(t *testing.T) {
+	set, err := ReadGob(&bytes.Buffer{})
+	require.NoError(t, err, "Error reading empty gob")
+	assert.Zero(t, set.Len(), "Expected no pairs")
+}
@@ -0,0 +1,238 @@
+package fnplot
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/palette"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// argScalars returns the scalar value of a single input argument of every pair
+// in the set.
+func (set *ValuesSet) argScalars(arg int) ([]*big.Float, error) {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+
+	samples, err := set.samplesLocked()
+	if err != nil {
+		return nil, err
+	}
+	scalars := make([]*big.Float, len(samples))
+	for i := range samples {
+		if arg >= len(samples[i].Input) {
+			return nil, withKind(ErrScalarConversion, fmt.Errorf("input %d has no argument %d", i, arg))
+		}
+		scalars[i], err = Values{samples[i].Input[arg]}.Scalar()
+		if err != nil {
+			return nil, withKind(ErrScalarConversion,
+				errors.WithMessage(err, fmt.Sprintf("error converting argument %d of input %d to int", arg, i)))
+		}
+	}
+	return scalars, nil
+}
+
+// heatmapGrid is a plotter.GridXYZ that holds the mean Z value, or the number,
+// of the points in each cell of a regular grid.
+type heatmapGrid struct {
+	minX, minY    float64
+	width, height float64
+	z             [][]float64
+}
+
+// newHeatmapGrid divides the range of the points into cols*rows cells and
+// calculates the mean Z value of the points in each cell. If zs is nil, the
+// value of each cell is the number of points in it instead. Cells without any
+// points have a NaN value.
+func newHeatmapGrid(cols, rows int, xs, ys, zs []float64) *heatmapGrid {
+	g := &heatmapGrid{
+		minX: math.Inf(1),
+		minY: math.Inf(1),
+		z:    make([][]float64, cols),
+	}
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for i := range xs {
+		g.minX, maxX = math.Min(g.minX, xs[i]), math.Max(maxX, xs[i])
+		g.minY, maxY = math.Min(g.minY, ys[i]), math.Max(maxY, ys[i])
+	}
+	g.width = (maxX - g.minX) / float64(cols)
+	if g.width == 0 || len(xs) == 0 {
+		g.width = 1
+	}
+	g.height = (maxY - g.minY) / float64(rows)
+	if g.height == 0 || len(ys) == 0 {
+		g.height = 1
+	}
+
+	counts := make([][]int, cols)
+	for c := range g.z {
+		g.z[c] = make([]float64, rows)
+		counts[c] = make([]int, rows)
+	}
+	for i := range xs {
+		c := int((xs[i] - g.minX) / g.width)
+		if c >= cols {
+			c = cols - 1
+		}
+		r := int((ys[i] - g.minY) / g.height)
+		if r >= rows {
+			r = rows - 1
+		}
+		if zs != nil {
+			g.z[c][r] += zs[i]
+		}
+		counts[c][r]++
+	}
+	for c := range g.z {
+		for r := range g.z[c] {
+			switch {
+			case counts[c][r] == 0:
+				g.z[c][r] = math.NaN()
+			case zs == nil:
+				g.z[c][r] = float64(counts[c][r])
+			default:
+				g.z[c][r] /= float64(counts[c][r])
+			}
+		}
+	}
+	return g
+}
+
+func (g *heatmapGrid) Dims() (c, r int)   { return len(g.z), len(g.z[0]) }
+func (g *heatmapGrid) Z(c, r int) float64 { return g.z[c][r] }
+func (g *heatmapGrid) X(c int) float64    { return g.minX + (float64(c)+0.5)*g.width }
+func (g *heatmapGrid) Y(r int) float64    { return g.minY + (float64(r)+0.5)*g.height }
+
+// addDensity adds a 2D histogram of the points of all series to the plot. The
+// color of each cell is the number of points in it. There are bins columns and
+// bins/5 rows of cells, which are about square on the default plot size.
+func addDensity(p *plot.Plot, bins int, points []plotter.XYs) error {
+	if bins <= 0 {
+		bins = defaultDensityBins
+	}
+	rows := bins / 5
+	if rows < 1 {
+		rows = 1
+	}
+	var xs, ys []float64
+	for _, pts := range points {
+		for _, pt := range pts {
+			if math.IsInf(pt.X, 0) || math.IsInf(pt.Y, 0) {
+				return plotter.ErrInfinity
+			}
+			xs = append(xs, pt.X)
+			ys = append(ys, pt.Y)
+		}
+	}
+	if len(xs) == 0 {
+		return nil
+	}
+
+	density := plotter.NewHeatMap(
+		newHeatmapGrid(bins, rows, xs, ys, nil),
+		palette.Rainbow(64, palette.Blue, palette.Red, 1, 1, 1))
+	if density.Min == density.Max {
+		// Widen the range so that every cell maps to a palette color.
+		density.Max++
+	}
+	p.Add(density)
+	return nil
+}
+
+// A HeatmapPlot plots a function of two inputs as a heatmap. The X and Y
+// coordinates are the first and second input arguments and the color of each
+// cell is the mean output of the samples in that cell.
+type HeatmapPlot struct {
+	Title string
+	Fn    Fn
+	// X and Y are the axes of the first and second input arguments.
+	X, Y Axis
+	// Z converts the output scalar values to the values mapped to colors. If
+	// nil, a StdAxix is used.
+	Z Axis
+	// Cols and Rows are the number of cells in each row and column of the
+	// heatmap. If zero, 50 are used.
+	Cols, Rows int
+	// Palette is the color palette of the heatmap. If nil, palette.Heat is
+	// used.
+	Palette palette.Palette
+	// Theme sets the background, grid, and fonts of the plot. The cell colors
+	// are always from Palette.
+	Theme Theme
+}
+
+// Save writes the heatmap as an image to the given filename. The image format
+// is determined by the file extension.
+func (hp HeatmapPlot) Save(filename string) error {
+	set := hp.Fn.ValuesSet()
+	inputXs, err := set.argScalars(0)
+	if err != nil {
+		return errors.WithMessage(err, "error converting first argument values")
+	}
+	inputYs, err := set.argScalars(1)
+	if err != nil {
+		return errors.WithMessage(err, "error converting second argument values")
+	}
+	_, outputs, err := set.scalars()
+	if err != nil {
+		return errors.WithMessage(err, "error converting output values")
+	}
+	if len(outputs) == 0 {
+		return errors.WithMessage(ErrNoSamples, "cannot plot a heatmap without any values")
+	}
+
+	zAxis := hp.Z
+	if zAxis == nil {
+		zAxis = &StdAxix{}
+	}
+	setValuesOn(hp.X, inputXs)
+	setValuesOn(hp.Y, inputYs)
+	setValuesOn(zAxis, outputs)
+	xs := make([]float64, len(outputs))
+	ys := make([]float64, len(outputs))
+	zs := make([]float64, len(outputs))
+	for i := range outputs {
+		xs[i] = hp.X.Point(inputXs[i])
+		ys[i] = hp.Y.Point(inputYs[i])
+		zs[i] = zAxis.Point(outputs[i])
+	}
+
+	cols, rows := hp.Cols, hp.Rows
+	if cols <= 0 {
+		cols = 50
+	}
+	if rows <= 0 {
+		rows = 50
+	}
+	pal := hp.Palette
+	if pal == nil {
+		pal = palette.Heat(64, 1)
+	}
+	heatmap := plotter.NewHeatMap(newHeatmapGrid(cols, rows, xs, ys, zs), pal)
+	if heatmap.Min == heatmap.Max {
+		// Widen the range so that every cell maps to a palette color.
+		heatmap.Max++
+	}
+
+	p, err := plot.New()
+	if err != nil {
+		return withKind(ErrRender, errors.WithMessage(err, "error creating plot"))
+	}
+	if err := hp.Theme.apply(p); err != nil {
+		return withKind(ErrRender, err)
+	}
+	p.Title.Text = hp.Title
+	p.X.Label.Text = " "
+	p.Y.Label.Text = " "
+	p.X.Tick.Marker = tickerFor(hp.X, nil)
+	p.Y.Tick.Marker = tickerFor(hp.Y, nil)
+	p.Add(heatmap)
+
+	// Save the plot to a file. The format is determined by the file extension.
+	err = p.Save(10*vg.Inch, 8*vg.Inch, filename)
+	return withKind(ErrRender, errors.WithMessage(err, "error writing plot image"))
+}
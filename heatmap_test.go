@@ -0,0 +1,37 @@
+package fnplot
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeatmapGrid(t *testing.T) {
+	xs := []float64{0, 1, 9, 10, 10}
+	ys := []float64{0, 1, 0, 10, 10}
+	zs := []float64{1, 3, 5, 6, 8}
+	grid := newHeatmapGrid(2, 2, xs, ys, zs)
+
+	c, r := grid.Dims()
+	assert.Equal(t, 2, c, "Expected and actual columns are different")
+	assert.Equal(t, 2, r, "Expected and actual rows are different")
+	assert.Equal(t, 2.5, grid.X(0), "Expected and actual X coordinates are different")
+	assert.Equal(t, 7.5, grid.Y(1), "Expected and actual Y coordinates are different")
+
+	assert.Equal(t, float64(2), grid.Z(0, 0), "Expected the mean of the values in the cell")
+	assert.Equal(t, float64(5), grid.Z(1, 0), "Expected the value in the cell")
+	assert.Equal(t, float64(7), grid.Z(1, 1), "Expected the values on the max edge in the last cell")
+	assert.True(t, math.IsNaN(grid.Z(0, 1)), "Expected an empty cell to be NaN")
+}
+
+func TestHeatmapGridDensity(t *testing.T) {
+	xs := []float64{0, 1, 2, 9, 10}
+	ys := []float64{0, 1, 2, 0, 10}
+	grid := newHeatmapGrid(2, 2, xs, ys, nil)
+
+	assert.Equal(t, float64(3), grid.Z(0, 0), "Expected the number of points in the cell")
+	assert.Equal(t, float64(1), grid.Z(1, 0), "Expected the number of points in the cell")
+	assert.Equal(t, float64(1), grid.Z(1, 1), "Expected the number of points in the cell")
+	assert.True(t, math.IsNaN(grid.Z(0, 1)), "Expected an empty cell to be NaN")
+}
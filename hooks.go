@@ -0,0 +1,49 @@
+package fnplot
+
+import (
+	"reflect"
+	"time"
+)
+
+// Hooks are called around each call of a sampled function, like for logging,
+// tracing, or collecting other metrics of each sample. The hooks of concurrent
+// workers are called concurrently.
+type Hooks struct {
+	// Before, if not nil, is called with the input before the function is
+	// called.
+	Before func(input Values)
+	// After, if not nil, is called with the input and output after the
+	// function returns, along with how long the function took. The output
+	// doesn't include a Tag, Labels, or error last return value. err is the
+	// error returned by the function, or a *Failure if the function panicked.
+	After func(input, output Values, err error, d time.Duration)
+}
+
+// WithHooks returns a copy of the Fn that calls the hooks around each call of
+// the function. The copy samples into the same ValuesSet.
+func (fn Fn) WithHooks(hooks Hooks) Fn {
+	return fn.withCaller(hookCaller(fn.call, hooks))
+}
+
+// hookCaller returns a caller that calls the hooks around each call.
+func hookCaller(call caller, hooks Hooks) caller {
+	return func(fnVal reflect.Value, args []reflect.Value) ([]reflect.Value, *Failure) {
+		if hooks.Before != nil {
+			hooks.Before(Values(args))
+		}
+		start := time.Now()
+		results, failure := call(fnVal, args)
+		d := time.Since(start)
+		if hooks.After != nil {
+			var output []reflect.Value
+			var err error
+			if failure != nil {
+				err = failure
+			} else {
+				output, _, err = splitResults(results)
+			}
+			hooks.After(Values(args), Values(output), err, d)
+		}
+		return results, failure
+	}
+}
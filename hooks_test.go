@@ -0,0 +1,43 @@
+package fnplot
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithHooks(t *testing.T) {
+	var mu sync.Mutex
+	var before, after []string
+	var errs []error
+	fn := newFn(func(i int) (int, Tag, error) {
+		if i == 2 {
+			return 0, "", errors.New("two")
+		}
+		return i * 10, "tagged", nil
+	}, NewValuesSet(NewMemoryStore(0)), Sequence(0, 4, 1)).WithHooks(Hooks{
+		Before: func(input Values) {
+			mu.Lock()
+			defer mu.Unlock()
+			before = append(before, input.String())
+		},
+		After: func(input, output Values, err error, d time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			after = append(after, input.String()+" "+output.String())
+			errs = append(errs, err)
+			assert.True(t, d >= 0, "Expected the duration of the call")
+		},
+	})
+
+	require.NoError(t, fn.RunWithConfig(4, RunConfig{Workers: 1}), "Error running function")
+	assert.Equal(t, []string{"0", "1", "2", "3"}, before, "Expected Before to be called with each input")
+	assert.Equal(t, []string{"0 0", "1 10", "2 0", "3 30"}, after,
+		"Expected After to be called with each input and output")
+	assert.Equal(t, []error{nil, nil, errors.New("two"), nil}, errs, "Expected After to be called with the errors")
+	assert.Equal(t, 3, fn.ValuesSet().Len(), "Expected the pairs to be sampled into the set")
+}
@@ -0,0 +1,363 @@
+package fnplot
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"image/color"
+	"io"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// formatted returns the formatted input and output values of every sample in
+// the set, in the same order as scalars.
+func (set *ValuesSet) formatted() (inputs, outputs []string, err error) {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	samples, err := set.samplesLocked()
+	if err != nil {
+		return nil, nil, err
+	}
+	inputs, outputs = sampleStrings(samples)
+	return inputs, outputs, nil
+}
+
+// sampleStrings returns the formatted input and output values of the samples.
+func sampleStrings(samples []Sample) (inputs, outputs []string) {
+	inputs = make([]string, len(samples))
+	outputs = make([]string, len(samples))
+	for i := range samples {
+		inputs[i] = samples[i].Input.String()
+		outputs[i] = samples[i].Output.String()
+	}
+	return inputs, outputs
+}
+
+// htmlPoint is a point in an HTML plot along with the formatted input and output
+// values that it represents.
+type htmlPoint struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Input  string  `json:"input"`
+	Output string  `json:"output"`
+}
+
+type htmlSeries struct {
+	Name   string      `json:"name"`
+	Color  string      `json:"color"`
+	Points []htmlPoint `json:"points"`
+}
+
+type htmlPlot struct {
+	Title      string       `json:"title"`
+	XLabel     string       `json:"xLabel"`
+	YLabel     string       `json:"yLabel"`
+	Lines      bool         `json:"lines"`
+	Dots       bool         `json:"dots"`
+	Background string       `json:"background"`
+	Foreground string       `json:"foreground"`
+	Grid       string       `json:"grid"`
+	Series     []htmlSeries `json:"series"`
+}
+
+// cssColor formats the color as a CSS rgba() color.
+func cssColor(c color.Color) string {
+	r, g, b, a := c.RGBA()
+	if a == 0 {
+		return "rgba(0,0,0,0)"
+	}
+	return fmt.Sprintf("rgba(%d,%d,%d,%.3f)", r*0xff/a, g*0xff/a, b*0xff/a, float64(a)/0xffff)
+}
+
+// cssColorOr formats the color as a CSS color, or returns the default CSS color
+// if it's nil.
+func cssColorOr(c color.Color, def string) string {
+	if c == nil {
+		return def
+	}
+	return cssColor(c)
+}
+
+// htmlPlot converts the series of the plot to points with the formatted input
+// and output values of each point.
+func (pl Plot) htmlPlot() (htmlPlot, error) {
+	switch pl.Style {
+	case LinePoints, Line, Scatter:
+	default:
+		return htmlPlot{}, errors.Errorf("unsupported HTML plot style %d, use LinePoints, Line, or Scatter", pl.Style)
+	}
+	hp := htmlPlot{
+		Title:  pl.Title,
+		XLabel: pl.XLabel,
+		YLabel: pl.YLabel,
+		Lines:  pl.Style == LinePoints || pl.Style == Line,
+		Dots:   pl.Style != Line,
+
+		Background: cssColorOr(pl.Theme.Background, "#fff"),
+		Foreground: cssColorOr(pl.Theme.Foreground, "#000"),
+		Grid:       cssColorOr(pl.Theme.Grid, "#ddd"),
+	}
+	series := pl.series()
+	samples, inputs, outputs, err := pl.seriesSamples(series)
+	if err != nil {
+		return htmlPlot{}, err
+	}
+	styles := seriesStyles(pl.Theme, series)
+	for i := range series {
+		formattedInputs, formattedOutputs := sampleStrings(samples[i])
+		if pl.Inverse {
+			formattedInputs, formattedOutputs = formattedOutputs, formattedInputs
+		}
+		hs := htmlSeries{
+			Name:   series[i].Name,
+			Color:  cssColor(styles[i].Color),
+			Points: make([]htmlPoint, len(inputs[i])),
+		}
+		for j := range inputs[i] {
+			x, y := pl.X.Point(inputs[i][j]), pl.Y.Point(outputs[i][j])
+			if math.IsInf(x, 0) || math.IsNaN(x) || math.IsInf(y, 0) || math.IsNaN(y) {
+				return htmlPlot{}, withKind(ErrRender, errors.New("infinity or NaN value found, consider using an axis that supports scaling"))
+			}
+			hs.Points[j] = htmlPoint{
+				X:      x,
+				Y:      y,
+				Input:  formattedInputs[j],
+				Output: formattedOutputs[j],
+			}
+		}
+		sort.Slice(hs.Points, func(a, b int) bool { return hs.Points[a].X < hs.Points[b].X })
+		hp.Series = append(hp.Series, hs)
+	}
+	return hp, nil
+}
+
+// WriteHTML writes the plot as a self-contained interactive HTML page. The plot
+// can be zoomed with the mouse wheel, panned by dragging, and reset by double
+// clicking. Hovering over a point shows the original input and output values.
+// Only the LinePoints, Line, and Scatter styles are supported, and other styles
+// are an error.
+func (pl Plot) WriteHTML(w io.Writer) error {
+	hp, err := pl.htmlPlot()
+	if err != nil {
+		return errors.WithMessage(err, "error generating X,Y points")
+	}
+	data, err := json.Marshal(hp)
+	if err != nil {
+		return errors.WithMessage(err, "error encoding plot data")
+	}
+	err = htmlTemplate.Execute(w, struct {
+		Title string
+		Data  template.JS
+	}{
+		Title: pl.Title,
+		Data:  template.JS(data),
+	})
+	return errors.WithMessage(err, "error writing HTML")
+}
+
+// SaveHTML writes the plot as a self-contained interactive HTML page to the
+// given filename. See WriteHTML.
+func (pl Plot) SaveHTML(filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return errors.WithMessage(err, "error creating HTML file")
+	}
+	if err := pl.WriteHTML(f); err != nil {
+		f.Close()
+		return err
+	}
+	return errors.WithMessage(f.Close(), "error closing HTML file")
+}
+
+var htmlTemplate = template.Must(template.New("plot").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  body { margin: 0; font-family: sans-serif; }
+  #plot { display: block; width: 100vw; height: 90vh; cursor: crosshair; }
+  #tooltip {
+    position: absolute; display: none; pointer-events: none; padding: 4px 8px;
+    background: rgba(255,255,255,0.95); border: 1px solid #888; font-size: 12px;
+    white-space: pre;
+  }
+  #help { padding: 0 12px; color: #666; font-size: 12px; }
+</style>
+</head>
+<body>
+<canvas id="plot"></canvas>
+<div id="help">Scroll to zoom, drag to pan, double click to reset.</div>
+<div id="tooltip"></div>
+<script>
+(function() {
+  var plot = {{.Data}};
+  var canvas = document.getElementById("plot");
+  var tooltip = document.getElementById("tooltip");
+  var ctx = canvas.getContext("2d");
+  document.body.style.background = plot.background;
+  var margin = {left: 80, right: 20, top: 40, bottom: 50};
+
+  var all = [];
+  plot.series.forEach(function(s) { all = all.concat(s.points); });
+  function extent(key) {
+    var min = Infinity, max = -Infinity;
+    all.forEach(function(p) { min = Math.min(min, p[key]); max = Math.max(max, p[key]); });
+    if (!isFinite(min)) { min = 0; max = 1; }
+    if (min === max) { min -= 0.5; max += 0.5; }
+    var pad = (max - min) * 0.02;
+    return [min - pad, max + pad];
+  }
+  var full = {x: extent("x"), y: extent("y")};
+  var view = {x: full.x.slice(), y: full.y.slice()};
+
+  function width() { return canvas.width - margin.left - margin.right; }
+  function height() { return canvas.height - margin.top - margin.bottom; }
+  function toPx(p) {
+    return {
+      x: margin.left + (p.x - view.x[0]) / (view.x[1] - view.x[0]) * width(),
+      y: margin.top + (1 - (p.y - view.y[0]) / (view.y[1] - view.y[0])) * height()
+    };
+  }
+  function fromPx(px, py) {
+    return {
+      x: view.x[0] + (px - margin.left) / width() * (view.x[1] - view.x[0]),
+      y: view.y[0] + (1 - (py - margin.top) / height()) * (view.y[1] - view.y[0])
+    };
+  }
+  function ticks(min, max, n) {
+    var step = Math.pow(10, Math.floor(Math.log10((max - min) / n)));
+    var err = (max - min) / n / step;
+    if (err >= 7.5) step *= 10; else if (err >= 3.5) step *= 5; else if (err >= 1.5) step *= 2;
+    var out = [];
+    for (var v = Math.ceil(min / step) * step; v <= max; v += step) out.push(v);
+    return out;
+  }
+  function label(v) { return Math.abs(v) >= 1e6 || (v !== 0 && Math.abs(v) < 1e-3) ? v.toExponential(2) : +v.toPrecision(6) + ""; }
+
+  function draw() {
+    canvas.width = canvas.clientWidth;
+    canvas.height = canvas.clientHeight;
+    ctx.fillStyle = plot.background;
+    ctx.fillRect(0, 0, canvas.width, canvas.height);
+
+    ctx.fillStyle = plot.foreground;
+    ctx.font = "16px sans-serif";
+    ctx.textAlign = "center";
+    ctx.fillText(plot.title, canvas.width / 2, 24);
+    ctx.font = "12px sans-serif";
+    ctx.fillText(plot.xLabel, margin.left + width() / 2, canvas.height - 8);
+    ctx.save();
+    ctx.translate(14, margin.top + height() / 2);
+    ctx.rotate(-Math.PI / 2);
+    ctx.fillText(plot.yLabel, 0, 0);
+    ctx.restore();
+
+    ctx.strokeStyle = plot.grid;
+    ctx.textAlign = "center";
+    ticks(view.x[0], view.x[1], 10).forEach(function(v) {
+      var px = toPx({x: v, y: 0}).x;
+      ctx.beginPath(); ctx.moveTo(px, margin.top); ctx.lineTo(px, margin.top + height()); ctx.stroke();
+      ctx.fillText(label(v), px, margin.top + height() + 16);
+    });
+    ctx.textAlign = "right";
+    ticks(view.y[0], view.y[1], 6).forEach(function(v) {
+      var py = toPx({x: 0, y: v}).y;
+      ctx.beginPath(); ctx.moveTo(margin.left, py); ctx.lineTo(margin.left + width(), py); ctx.stroke();
+      ctx.fillText(label(v), margin.left - 6, py + 4);
+    });
+    ctx.strokeStyle = plot.foreground;
+    ctx.strokeRect(margin.left, margin.top, width(), height());
+
+    ctx.save();
+    ctx.beginPath();
+    ctx.rect(margin.left, margin.top, width(), height());
+    ctx.clip();
+    plot.series.forEach(function(s) {
+      ctx.strokeStyle = s.color;
+      if (plot.lines) {
+        ctx.beginPath();
+        s.points.forEach(function(p, i) {
+          var px = toPx(p);
+          if (i === 0) ctx.moveTo(px.x, px.y); else ctx.lineTo(px.x, px.y);
+        });
+        ctx.stroke();
+      }
+      if (plot.dots) {
+        s.points.forEach(function(p) {
+          var px = toPx(p);
+          ctx.beginPath(); ctx.arc(px.x, px.y, 3, 0, 2 * Math.PI); ctx.stroke();
+        });
+      }
+    });
+    ctx.restore();
+
+    ctx.textAlign = "left";
+    plot.series.forEach(function(s, i) {
+      var y = margin.top + 16 + i * 16;
+      ctx.fillStyle = s.color;
+      ctx.fillRect(margin.left + width() - 120, y - 8, 10, 10);
+      ctx.fillStyle = plot.foreground;
+      ctx.fillText(s.name, margin.left + width() - 104, y + 1);
+    });
+  }
+
+  function nearest(mx, my) {
+    var best = null, bestDist = 100;
+    plot.series.forEach(function(s) {
+      s.points.forEach(function(p) {
+        var px = toPx(p);
+        var d = (px.x - mx) * (px.x - mx) + (px.y - my) * (px.y - my);
+        if (d < bestDist) { best = {series: s, point: p}; bestDist = d; }
+      });
+    });
+    return best;
+  }
+
+  var drag = null;
+  canvas.addEventListener("mousedown", function(e) {
+    drag = {x: e.offsetX, y: e.offsetY, view: {x: view.x.slice(), y: view.y.slice()}};
+  });
+  window.addEventListener("mouseup", function() { drag = null; });
+  canvas.addEventListener("mousemove", function(e) {
+    if (drag) {
+      var dx = (e.offsetX - drag.x) / width() * (drag.view.x[1] - drag.view.x[0]);
+      var dy = (e.offsetY - drag.y) / height() * (drag.view.y[1] - drag.view.y[0]);
+      view.x = [drag.view.x[0] - dx, drag.view.x[1] - dx];
+      view.y = [drag.view.y[0] + dy, drag.view.y[1] + dy];
+      draw();
+      return;
+    }
+    var hit = nearest(e.offsetX, e.offsetY);
+    if (!hit) { tooltip.style.display = "none"; return; }
+    tooltip.textContent = hit.series.name +
+      "\ninput: " + hit.point.input + "\noutput: " + hit.point.output +
+      "\nx: " + label(hit.point.x) + ", y: " + label(hit.point.y);
+    tooltip.style.left = (e.pageX + 12) + "px";
+    tooltip.style.top = (e.pageY + 12) + "px";
+    tooltip.style.display = "block";
+  });
+  canvas.addEventListener("mouseleave", function() { tooltip.style.display = "none"; });
+  canvas.addEventListener("wheel", function(e) {
+    e.preventDefault();
+    var at = fromPx(e.offsetX, e.offsetY);
+    var factor = e.deltaY < 0 ? 0.8 : 1.25;
+    view.x = [at.x - (at.x - view.x[0]) * factor, at.x + (view.x[1] - at.x) * factor];
+    view.y = [at.y - (at.y - view.y[0]) * factor, at.y + (view.y[1] - at.y) * factor];
+    draw();
+  }, {passive: false});
+  canvas.addEventListener("dblclick", function() {
+    view = {x: full.x.slice(), y: full.y.slice()};
+    draw();
+  });
+  window.addEventListener("resize", draw);
+  draw();
+})();
+</script>
+</body>
+</html>
+`))
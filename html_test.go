@@ -0,0 +1,109 @@
+package fnplot
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"math/big"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// htmlData matches the plot data embedded in an HTML plot.
+var htmlData = regexp.MustCompile(`var plot = (.*);\n`)
+
+// decodeHTML writes the plot as HTML and decodes the embedded plot data.
+func decodeHTML(t *testing.T, pl Plot) htmlPlot {
+	var buf bytes.Buffer
+	require.NoError(t, pl.WriteHTML(&buf), "Error writing HTML plot")
+	match := htmlData.FindSubmatch(buf.Bytes())
+	require.NotNil(t, match, "Expected the plot data in the HTML")
+	var hp htmlPlot
+	require.NoError(t, json.Unmarshal(match[1], &hp), "Error decoding plot data")
+	return hp
+}
+
+func TestWriteHTML(t *testing.T) {
+	pl := Plot{
+		Title: "Squares",
+		X:     &StdAxix{},
+		Y:     &StdAxix{},
+		Style: Scatter,
+	}
+	pl.AddSeries("squares", FnOf(newTestSet(t, []float64{3, 1, 2}, []float64{9, 1, 4})))
+	pl.AddSeries("cubes", FnOf(newTestSet(t, []float64{2}, []float64{8})))
+
+	hp := decodeHTML(t, pl)
+	assert.Equal(t, "Squares", hp.Title, "Expected the title")
+	assert.False(t, hp.Lines, "Expected no lines for the Scatter style")
+	assert.True(t, hp.Dots, "Expected dots for the Scatter style")
+	require.Len(t, hp.Series, 2, "Expected a series for each function")
+	assert.Equal(t, "squares", hp.Series[0].Name, "Expected the name of the series")
+	assert.Equal(t, []htmlPoint{
+		{X: 1, Y: 1, Input: "1", Output: "1"},
+		{X: 2, Y: 4, Input: "2", Output: "4"},
+		{X: 3, Y: 9, Input: "3", Output: "9"},
+	}, hp.Series[0].Points, "Expected the points sorted by X with their formatted values")
+
+	pl.Inverse = true
+	hp = decodeHTML(t, pl)
+	assert.Equal(t, htmlPoint{X: 1, Y: 1, Input: "1", Output: "1"}, hp.Series[0].Points[0], "Expected the first inverse point")
+	assert.Equal(t, htmlPoint{X: 8, Y: 2, Input: "8", Output: "2"}, hp.Series[1].Points[0],
+		"Expected the formatted values of inverse points to be swapped")
+}
+
+func TestWriteHTMLErrors(t *testing.T) {
+	set := newTestSet(t, []float64{1, 2}, []float64{1, 4})
+	huge := func(Values) (*big.Float, error) {
+		return new(big.Float).SetMantExp(big.NewFloat(1), 5000), nil
+	}
+	tests := []struct {
+		description string
+		pl          Plot
+		cause       error
+	}{
+		{
+			description: "unsupported style",
+			pl:          Plot{Fn: FnOf(set), X: &StdAxix{}, Y: &StdAxix{}, Style: BoxPlot},
+		},
+		{
+			description: "infinite points",
+			pl:          Plot{Fn: FnOf(set), X: &StdAxix{}, Y: &StdAxix{}, Scalars: ScalarConfig{Output: huge}},
+			cause:       ErrRender,
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			err := test.pl.WriteHTML(&bytes.Buffer{})
+			require.Error(t, err, "Expected an error writing the HTML plot")
+			if test.cause != nil {
+				assert.Equal(t, test.cause, errors.Cause(err), "Expected the cause of the error")
+			}
+		})
+	}
+}
+
+func TestWriteHTMLWhileSampling(t *testing.T) {
+	fn := NewFn(math.Sqrt, 10, Float64Range(0, 100))
+	pl := Plot{Fn: fn, X: &StdAxix{}, Y: &StdAxix{}}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		fn.run(500)
+	}()
+	for i := 0; i < 20; i++ {
+		hp := decodeHTML(t, pl)
+		for _, p := range hp.Series[0].Points {
+			assert.Equal(t, p.Input, NewValues(p.X).String(), "Expected the formatted input of each point")
+		}
+	}
+	wg.Wait()
+}
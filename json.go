@@ -0,0 +1,118 @@
+package fnplot
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// jsonPair is an input/output pair as persisted in JSON. The scalar values are
+// decimal strings so that they keep more precision than a JSON number.
+type jsonPair struct {
+	Input        string            `json:"input"`
+	Output       string            `json:"output"`
+	InputValues  string            `json:"inputValues"`
+	OutputValues string            `json:"outputValues"`
+	Labels       map[string]string `json:"labels,omitempty"`
+}
+
+type jsonValuesSet struct {
+	Pairs []jsonPair `json:"pairs"`
+}
+
+// MarshalJSON encodes the scalar values and the formatted values of every pair
+// in the set as JSON.
+func (set *ValuesSet) MarshalJSON() ([]byte, error) {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	samples, err := set.samplesLocked()
+	if err != nil {
+		return nil, err
+	}
+	inputs, outputs, err := sampleScalars(samples)
+	if err != nil {
+		return nil, err
+	}
+	formattedInputs, formattedOutputs := sampleStrings(samples)
+	js := jsonValuesSet{Pairs: make([]jsonPair, len(inputs))}
+	for i := range inputs {
+		js.Pairs[i] = jsonPair{
+			Input:        inputs[i].Text('g', -1),
+			Output:       outputs[i].Text('g', -1),
+			InputValues:  formattedInputs[i],
+			OutputValues: formattedOutputs[i],
+			Labels:       samples[i].Labels,
+		}
+	}
+	return json.Marshal(js)
+}
+
+// UnmarshalJSON replaces the pairs in the set with the pairs encoded by
+// MarshalJSON, kept in a MemoryStore. The original input and output values
+// can't be restored, so each loaded pair keeps its formatted values as strings
+// and the saved scalar values are used as-is.
+func (set *ValuesSet) UnmarshalJSON(data []byte) error {
+	var js jsonValuesSet
+	if err := json.Unmarshal(data, &js); err != nil {
+		return errors.WithMessage(err, "error decoding values set JSON")
+	}
+	samples := make([]Sample, len(js.Pairs))
+	for i, jp := range js.Pairs {
+		in, err := parseScalar(jp.Input)
+		if err != nil {
+			return errors.WithMessage(err, "error parsing input scalar of pair "+strconv.Itoa(i))
+		}
+		out, err := parseScalar(jp.Output)
+		if err != nil {
+			return errors.WithMessage(err, "error parsing output scalar of pair "+strconv.Itoa(i))
+		}
+		samples[i] = Sample{
+			Input:        NewValues(jp.InputValues),
+			Output:       NewValues(jp.OutputValues),
+			InputScalar:  in,
+			OutputScalar: out,
+			Labels:       jp.Labels,
+		}
+	}
+
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.store = &MemoryStore{samples: samples}
+	set.sampled = len(samples)
+	return nil
+}
+
+// parseScalar parses a decimal scalar value formatted with Text('g', -1). The
+// shortest decimal only identifies the original value at the original
+// precision, so it's parsed at the precision set with SetPrecision.
+func parseScalar(s string) (*big.Float, error) {
+	f, _, err := big.ParseFloat(s, 10, Precision(), big.ToNearestEven)
+	return f, err
+}
+
+// SaveJSON writes the set as JSON to the given filename so that it can be
+// plotted later without running the function again.
+func (set *ValuesSet) SaveJSON(filename string) error {
+	data, err := set.MarshalJSON()
+	if err != nil {
+		return errors.WithMessage(err, "error encoding values set JSON")
+	}
+	err = ioutil.WriteFile(filename, data, 0644)
+	return errors.WithMessage(err, "error writing values set JSON file")
+}
+
+// LoadJSON reads a set written by SaveJSON from the given filename.
+func LoadJSON(filename string) (*ValuesSet, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error reading values set JSON file")
+	}
+	set := &ValuesSet{}
+	if err := set.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
@@ -0,0 +1,43 @@
+package fnplot
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValuesSetJSON(t *testing.T) {
+	set := &ValuesSet{}
+	require.NoError(t, set.insert(NewValues(1.5), NewValues(2.25)), "Error inserting values")
+	require.NoError(t, set.insert(NewValues("a", "b"), NewValues(-3.0)), "Error inserting values")
+	require.NoError(t, set.insert(NewValues(uint64(1<<63+1)), NewValues(0.0)), "Error inserting values")
+
+	data, err := json.Marshal(set)
+	require.NoError(t, err, "Error encoding JSON")
+	loaded := &ValuesSet{}
+	require.NoError(t, json.Unmarshal(data, loaded), "Error decoding JSON")
+
+	expectedInputs, expectedOutputs, err := set.scalars()
+	require.NoError(t, err, "Error converting values")
+	actualInputs, actualOutputs, err := loaded.scalars()
+	require.NoError(t, err, "Error converting loaded values")
+	require.Len(t, actualInputs, len(expectedInputs), "Expected and actual number of pairs are different")
+	for i := range expectedInputs {
+		assert.Zero(t, expectedInputs[i].Cmp(actualInputs[i]), "Expected and actual inputs are different")
+		assert.Zero(t, expectedOutputs[i].Cmp(actualOutputs[i]), "Expected and actual outputs are different")
+	}
+
+	formattedInputs, _, err := loaded.formatted()
+	require.NoError(t, err, "Error formatting loaded values")
+	assert.Equal(t, []string{"1.5", "a, b", "9223372036854775809"}, formattedInputs, "Expected and actual formatted inputs are different")
+	_, _, minOutput, _ := loaded.MinMax()
+	assert.Zero(t, big.NewFloat(-3).Cmp(minOutput), "Expected and actual smallest outputs are different")
+}
+
+func TestValuesSetJSONError(t *testing.T) {
+	set := &ValuesSet{}
+	assert.Error(t, json.Unmarshal([]byte(`{"pairs":[{"input":"x","output":"1"}]}`), set), "Expected an error for an invalid scalar")
+}
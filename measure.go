@@ -0,0 +1,162 @@
+package fnplot
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// A MeasureConfig controls how Measure times each input of a function.
+type MeasureConfig struct {
+	// Warmup is the number of times the function is called with each input
+	// before it's timed, like for filling caches.
+	Warmup int
+	// Repetitions is the number of times the function is timed with each
+	// input. The mean duration is the output. If zero, the function is timed
+	// once.
+	Repetitions int
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Measure returns a function with the same inputs as fn whose output is how
+// long fn takes to run with the inputs, so the latency of fn can be plotted
+// against its input. A Tag, Labels, or error last return value of fn is kept as
+// a return value of the measured function (see measured). If fn isn't a
+// function, it's returned as is.
+func Measure(fn interface{}, config MeasureConfig) interface{} {
+	fnVal := reflect.ValueOf(fn)
+	if fnVal.Kind() != reflect.Func {
+		return fn
+	}
+	reps := config.Repetitions
+	if reps <= 0 {
+		reps = 1
+	}
+	return measured(fnVal, durationType, func(args []reflect.Value) (reflect.Value, []reflect.Value) {
+		for i := 0; i < config.Warmup; i++ {
+			callFn(fnVal, args)
+		}
+		var results []reflect.Value
+		start := time.Now()
+		for i := 0; i < reps; i++ {
+			results = callFn(fnVal, args)
+		}
+		return reflect.ValueOf(time.Since(start) / time.Duration(reps)), results
+	})
+}
+
+// NewTimedFn is like NewFn, but plots how long the function takes to run with
+// each input (see Measure), sampling with a single worker so that concurrent
+// calls don't skew the durations.
+func NewTimedFn(fn interface{}, samples int, config MeasureConfig, gens ...Generator) Fn {
+	return NewFnWithConfig(Measure(fn, config), samples, RunConfig{Workers: 1}, gens...)
+}
+
+// An AllocMetric is the measurement of the memory allocated by a function.
+type AllocMetric int
+
+const (
+	// AllocBytes is the number of bytes allocated.
+	AllocBytes AllocMetric = iota
+	// AllocObjects is the number of heap objects allocated.
+	AllocObjects
+)
+
+// allocMu serializes measuring allocations, since the memory statistics are
+// process wide.
+var allocMu sync.Mutex
+
+// MeasureAllocs returns a function with the same inputs as fn whose output is
+// how much memory fn allocates per call with the inputs, as a uint64 of the
+// given metric, so the allocation complexity of fn can be plotted against its
+// input. The configured warmup calls aren't measured, and the mean of the
+// repetitions is the output. A Tag, Labels, or error last return value of fn is
+// kept as a return value of the measured function (see measured). If fn isn't a
+// function, it's returned as is.
+//
+// The allocations are the difference of the process wide memory statistics
+// (see runtime.MemStats), so run the measured function with a single worker
+// (see NewAllocsFn) to keep the allocations of other goroutines out of the
+// measurements. The measurements include the few allocations of calling fn
+// through reflection.
+func MeasureAllocs(fn interface{}, metric AllocMetric, config MeasureConfig) interface{} {
+	fnVal := reflect.ValueOf(fn)
+	if fnVal.Kind() != reflect.Func {
+		return fn
+	}
+	reps := config.Repetitions
+	if reps <= 0 {
+		reps = 1
+	}
+	read := func(stats *runtime.MemStats) uint64 {
+		runtime.ReadMemStats(stats)
+		if metric == AllocObjects {
+			return stats.Mallocs
+		}
+		return stats.TotalAlloc
+	}
+	return measured(fnVal, reflect.TypeOf(uint64(0)), func(args []reflect.Value) (reflect.Value, []reflect.Value) {
+		for i := 0; i < config.Warmup; i++ {
+			callFn(fnVal, args)
+		}
+		allocMu.Lock()
+		defer allocMu.Unlock()
+		var stats runtime.MemStats
+		var results []reflect.Value
+		before := read(&stats)
+		for i := 0; i < reps; i++ {
+			results = callFn(fnVal, args)
+		}
+		allocs := read(&stats) - before
+		return reflect.ValueOf(allocs / uint64(reps)), results
+	})
+}
+
+// NewAllocsFn is like NewFn, but plots how much memory the function allocates
+// with each input (see MeasureAllocs), sampling with a single worker.
+func NewAllocsFn(fn interface{}, samples int, metric AllocMetric, config MeasureConfig, gens ...Generator) Fn {
+	return NewFnWithConfig(MeasureAllocs(fn, metric, config), samples, RunConfig{Workers: 1}, gens...)
+}
+
+// measured returns a function with the same inputs as fnVal whose output is
+// the measurement of type typ returned by measure, followed by the Tag or
+// Labels and the error of the results of fnVal, in the same order, if it returns
+// them. Errors are kept so that failing inputs are recorded as failures (see
+// ValuesSet.Failures) instead of plotted as measurements.
+func measured(fnVal reflect.Value, typ reflect.Type, measure func(args []reflect.Value) (reflect.Value, []reflect.Value)) interface{} {
+	fnType := fnVal.Type()
+	in := make([]reflect.Type, fnType.NumIn())
+	for i := range in {
+		in[i] = fnType.In(i)
+	}
+	out := []reflect.Type{typ}
+	// kept is the number of trailing results of fnVal that are kept, which are
+	// at most a Tag or Labels and an error.
+	kept := 0
+	for kept < 2 && kept < fnType.NumOut() {
+		last := fnType.Out(fnType.NumOut() - 1 - kept)
+		if last != tagType && last != labelsType && last != errorType {
+			break
+		}
+		kept++
+	}
+	for i := fnType.NumOut() - kept; i < fnType.NumOut(); i++ {
+		out = append(out, fnType.Out(i))
+	}
+	measuredType := reflect.FuncOf(in, out, fnType.IsVariadic())
+	return reflect.MakeFunc(measuredType, func(args []reflect.Value) []reflect.Value {
+		measurement, results := measure(args)
+		return append([]reflect.Value{measurement}, results[len(results)-kept:]...)
+	}).Interface()
+}
+
+// callFn calls fnVal with the arguments of a function made by measured. The
+// variadic arguments of a variadic function are the last argument as a slice.
+func callFn(fnVal reflect.Value, args []reflect.Value) []reflect.Value {
+	if fnVal.Type().IsVariadic() {
+		return fnVal.CallSlice(args)
+	}
+	return fnVal.Call(args)
+}
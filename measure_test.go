@@ -0,0 +1,127 @@
+package fnplot
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeasure(t *testing.T) {
+	var calls int32
+	fn := Measure(func(ms int) Tag {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(time.Duration(ms) * time.Millisecond)
+		return "slept"
+	}, MeasureConfig{Warmup: 1, Repetitions: 2})
+
+	measured, ok := fn.(func(int) (time.Duration, Tag))
+	require.True(t, ok, "Expected a function of the same inputs that returns a duration and the tag, got %T", fn)
+	d, tag := measured(5)
+	assert.Equal(t, int32(3), calls, "Expected a warmup call and a call for each repetition")
+	assert.True(t, d >= 5*time.Millisecond, "Expected the mean duration of the repetitions, got %s", d)
+	assert.Equal(t, Tag("slept"), tag, "Expected the tag of the function")
+
+	assert.Equal(t, 5, Measure(5, MeasureConfig{}), "Expected a non-function to be returned as is")
+
+	var sum int
+	fn = Measure(func(xs ...int) {
+		for _, x := range xs {
+			sum += x
+		}
+	}, MeasureConfig{})
+	variadic, ok := fn.(func(...int) time.Duration)
+	require.True(t, ok, "Expected a variadic function that returns a duration, got %T", fn)
+	variadic(1, 2, 3)
+	assert.Equal(t, 6, sum, "Expected the function to be called with the variadic arguments")
+}
+
+func TestNewTimedFn(t *testing.T) {
+	fn := NewTimedFn(func(ms int) {
+		time.Sleep(time.Duration(ms) * time.Millisecond)
+	}, 10, MeasureConfig{}, IntRange(0, 3))
+	inputs, outputs := setFloats(t, fn.ValuesSet())
+	require.Len(t, outputs, 10, "Expected a duration for each input")
+	for i := range inputs {
+		assert.True(t, outputs[i] >= inputs[i]*float64(time.Millisecond), "Expected the duration of input %v, got %v", inputs[i], outputs[i])
+	}
+}
+
+var allocSink []byte
+
+func TestMeasureAllocs(t *testing.T) {
+	alloc := func(n int) {
+		allocSink = make([]byte, n)
+	}
+	tests := []struct {
+		description string
+		metric      AllocMetric
+		n           int
+		min, max    uint64
+	}{
+		{description: "bytes", metric: AllocBytes, n: 1 << 20, min: 1 << 20, max: 1<<20 + 1024},
+		{description: "objects", metric: AllocObjects, n: 1 << 10, min: 1, max: 10},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			fn, ok := MeasureAllocs(alloc, test.metric, MeasureConfig{Warmup: 1, Repetitions: 3}).(func(int) uint64)
+			require.True(t, ok, "Expected a function of the same inputs that returns a uint64")
+			allocs := fn(test.n)
+			assert.True(t, allocs >= test.min && allocs <= test.max,
+				"Expected between %d and %d allocated, got %d", test.min, test.max, allocs)
+		})
+	}
+
+	fn, ok := MeasureAllocs(func(ns ...int) {
+		for _, n := range ns {
+			alloc(n)
+		}
+	}, AllocBytes, MeasureConfig{}).(func(...int) uint64)
+	require.True(t, ok, "Expected a variadic function that returns a uint64")
+	allocs := fn(1<<20, 1<<20)
+	assert.True(t, allocs >= 2<<20, "Expected the allocations of the variadic arguments, got %d", allocs)
+}
+
+func TestNewAllocsFn(t *testing.T) {
+	fn := NewAllocsFn(func(n int) {
+		allocSink = make([]byte, n)
+	}, 10, AllocBytes, MeasureConfig{}, IntRange(1<<10, 1<<16))
+	inputs, outputs := setFloats(t, fn.ValuesSet())
+	require.Len(t, outputs, 10, "Expected the allocations for each input")
+	for i := range inputs {
+		assert.True(t, outputs[i] >= inputs[i], "Expected at least %v bytes allocated, got %v", inputs[i], outputs[i])
+	}
+}
+
+func TestMeasureErrors(t *testing.T) {
+	fn := Measure(func(x int) (int, Tag, error) {
+		if x < 0 {
+			return 0, "negative", errors.New("negative input")
+		}
+		return x, "positive", nil
+	}, MeasureConfig{})
+
+	measured, ok := fn.(func(int) (time.Duration, Tag, error))
+	require.True(t, ok, "Expected a function that returns a duration, the tag, and the error, got %T", fn)
+	_, tag, err := measured(-1)
+	assert.Equal(t, Tag("negative"), tag, "Expected the tag of the function")
+	assert.EqualError(t, err, "negative input", "Expected the error of the function")
+
+	timed := NewTimedFn(func(x int) error {
+		if x < 0 {
+			return errors.New("negative input")
+		}
+		return nil
+	}, 20, MeasureConfig{}, IntRange(-5, 5))
+	set := timed.ValuesSet()
+	failures := set.Failures()
+	assert.NotEmpty(t, failures, "Expected the negative inputs to be recorded as failures")
+	assert.Equal(t, 20, set.Len()+len(failures), "Expected each input to be a measurement or a failure")
+	for _, failure := range failures {
+		assert.EqualError(t, failure.Err, "negative input", "Expected the error of the function")
+	}
+}
@@ -0,0 +1,182 @@
+package fnplot
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/plot/vg"
+)
+
+// A PlotOption configures a Plot created with NewPlot.
+type PlotOption func(pl *Plot)
+
+// WithTitle sets the title of the plot.
+func WithTitle(title string) PlotOption {
+	return func(pl *Plot) {
+		pl.Title = title
+	}
+}
+
+// WithFn sets the function to plot.
+func WithFn(fn Fn) PlotOption {
+	return func(pl *Plot) {
+		pl.Fn = fn
+	}
+}
+
+// WithFunc sets the function to plot, which NewPlot samples the number of
+// times set with WithSamples with inputs generated by the given generators.
+func WithFunc(fn interface{}, gens ...Generator) PlotOption {
+	return func(pl *Plot) {
+		pl.fn = fn
+		pl.gens = gens
+	}
+}
+
+// WithSamples sets the number of times NewPlot samples the function set with
+// WithFunc.
+func WithSamples(samples int) PlotOption {
+	return func(pl *Plot) {
+		pl.samples = samples
+	}
+}
+
+// WithAxes sets the X and Y axes of the plot.
+func WithAxes(x, y Axis) PlotOption {
+	return func(pl *Plot) {
+		pl.X, pl.Y = x, y
+	}
+}
+
+// WithLabels sets the labels of the X and Y axes.
+func WithLabels(x, y string) PlotOption {
+	return func(pl *Plot) {
+		pl.XLabel, pl.YLabel = x, y
+	}
+}
+
+// WithStyle sets how the points of the plot are drawn.
+func WithStyle(style PlotStyle) PlotOption {
+	return func(pl *Plot) {
+		pl.Style = style
+	}
+}
+
+// WithSize sets the width and height of the plot images.
+func WithSize(width, height vg.Length) PlotOption {
+	return func(pl *Plot) {
+		pl.Width, pl.Height = width, height
+	}
+}
+
+// WithSeries adds a named function to plot on the same axes.
+func WithSeries(name string, fn Fn) PlotOption {
+	return func(pl *Plot) {
+		pl.AddSeries(name, fn)
+	}
+}
+
+// WithTheme sets the theme of the plot.
+func WithTheme(th Theme) PlotOption {
+	return func(pl *Plot) {
+		pl.Theme = th
+	}
+}
+
+// NewPlot creates a plot configured by the options, validates it (see
+// Validate), and samples the function set with WithFunc, if any.
+func NewPlot(opts ...PlotOption) (Plot, error) {
+	return Plot{}.With(opts...)
+}
+
+// With returns a copy of the plot configured by the options, validated and
+// sampled like NewPlot. The copy plots the same sampled functions, so the
+// samples of one run can be drawn with different axes or styles without
+// running the functions again.
+func (pl Plot) With(opts ...PlotOption) (Plot, error) {
+	// Copy the series so that adding series to the copy doesn't change the
+	// series of the plot.
+	pl.Series = append([]Series(nil), pl.Series...)
+	for _, opt := range opts {
+		opt(&pl)
+	}
+	if err := pl.Validate(); err != nil {
+		return Plot{}, err
+	}
+	if pl.fn != nil {
+		pl.Fn = newFn(pl.fn, NewValuesSet(NewMemoryStore(0)), pl.gens...)
+		if err := pl.Fn.run(pl.samples); err != nil {
+			return Plot{}, errors.WithMessage(err, "error sampling function")
+		}
+		pl.fn, pl.gens = nil, nil
+	}
+	return pl, nil
+}
+
+// Validate returns a descriptive error if the plot can't be rendered, like a
+// plot without a function or axes, so mistakes are found before any work is
+// done.
+func (pl Plot) Validate() error {
+	if pl.fn != nil {
+		if pl.samples <= 0 {
+			return errors.Errorf("number of samples must be more than zero, got %d", pl.samples)
+		}
+	} else if len(pl.series()) == 0 {
+		return errors.New("plot has no function, set Fn or add a Series")
+	}
+	for _, s := range pl.series() {
+		if s.Fn.set == nil {
+			return errors.New("series " + s.Name + " has no ValuesSet, create it with NewFn or FnOf")
+		}
+		if pl.fn == nil && s.Fn.set.Len() == 0 {
+			return errors.WithMessage(ErrNoSamples, "series "+s.Name+" has no samples")
+		}
+	}
+	if pl.X == nil {
+		return errors.New("plot has no X axis")
+	}
+	if pl.Y == nil {
+		return errors.New("plot has no Y axis")
+	}
+	if pl.Width < 0 || pl.Height < 0 {
+		return errors.Errorf("plot size must not be negative, got %v by %v", pl.Width, pl.Height)
+	}
+	if pl.Style < LinePoints || pl.Style > Band {
+		return errors.Errorf("unknown plot style %d", pl.Style)
+	}
+	return nil
+}
+
+// ValidateFile returns a descriptive error if the plot can't be saved to the
+// given filename, like a plot that Validate reports or a filename with an
+// unsupported extension, so mistakes are found before any work is done.
+func (pl Plot) ValidateFile(filename string) error {
+	if err := checkExtension(filename); err != nil {
+		return err
+	}
+	return pl.Validate()
+}
+
+// imageFormats are the supported image formats of Write and Save.
+var imageFormats = map[string]bool{
+	"eps": true, "jpg": true, "jpeg": true, "pdf": true, "png": true, "svg": true, "tif": true, "tiff": true,
+}
+
+// checkFormat returns an error if the image format isn't supported.
+func checkFormat(format string) error {
+	if !imageFormats[strings.ToLower(format)] {
+		return errors.Errorf("unsupported image format %q", format)
+	}
+	return nil
+}
+
+// checkExtension returns an error if the image format of the file extension
+// isn't supported.
+func checkExtension(filename string) error {
+	ext := strings.TrimPrefix(filepath.Ext(filename), ".")
+	if ext == "" {
+		return errors.New("file " + filename + " has no extension to determine its image format")
+	}
+	return errors.WithMessage(checkFormat(ext), "unsupported file extension of "+filename)
+}
@@ -0,0 +1,138 @@
+package fnplot
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPlot(t *testing.T) {
+	pl, err := NewPlot(
+		WithTitle("math.Sqrt"),
+		WithFunc(math.Sqrt, Float64Range(0, 100)),
+		WithSamples(50),
+		WithAxes(&StdAxix{}, &StdAxix{}),
+		WithLabels("x", "sqrt(x)"),
+		WithStyle(Scatter),
+	)
+	require.NoError(t, err, "Error creating plot")
+	assert.Equal(t, "math.Sqrt", pl.Title, "Expected the title")
+	assert.Equal(t, 50, pl.Fn.ValuesSet().Len(), "Expected the function to be sampled")
+	assert.Equal(t, Scatter, pl.Style, "Expected the style")
+	assert.Equal(t, "sqrt(x)", pl.YLabel, "Expected the Y label")
+	var buf bytes.Buffer
+	assert.NoError(t, pl.Write(&buf, "svg"), "Error writing plot")
+}
+
+func TestValidate(t *testing.T) {
+	sampled := NewFn(math.Sqrt, 10, Float64Range(0, 100))
+	axes := WithAxes(&StdAxix{}, &StdAxix{})
+	tests := []struct {
+		description string
+		opts        []PlotOption
+		err         string
+	}{
+		{description: "no function", opts: []PlotOption{axes}, err: "plot has no function"},
+		{description: "zero samples", opts: []PlotOption{axes, WithFunc(math.Sqrt, Float64Range(0, 1))}, err: "number of samples"},
+		{description: "empty set", opts: []PlotOption{axes, WithFn(FnOf(NewValuesSet(nil)))}, err: "has no samples"},
+		{description: "no X axis", opts: []PlotOption{WithFn(sampled), WithAxes(nil, &StdAxix{})}, err: "no X axis"},
+		{description: "no Y axis", opts: []PlotOption{WithFn(sampled), WithAxes(&StdAxix{}, nil)}, err: "no Y axis"},
+		{description: "unknown style", opts: []PlotOption{axes, WithFn(sampled), WithStyle(PlotStyle(100))}, err: "unknown plot style"},
+		{description: "valid", opts: []PlotOption{axes, WithFn(sampled)}},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			_, err := NewPlot(test.opts...)
+			if test.err == "" {
+				assert.NoError(t, err, "Expected a valid plot")
+				return
+			}
+			require.Error(t, err, "Expected an invalid plot")
+			assert.Contains(t, err.Error(), test.err, "Expected a descriptive error")
+		})
+	}
+}
+
+func TestUnsupportedFormat(t *testing.T) {
+	pl := Plot{Fn: NewFn(math.Sqrt, 10, Float64Range(0, 100)), X: &StdAxix{}, Y: &StdAxix{}}
+	assert.Error(t, pl.Write(&bytes.Buffer{}, "gif"), "Expected an error writing an unsupported format")
+	assert.Error(t, pl.Save("plot.gif"), "Expected an error saving an unsupported extension")
+	assert.Error(t, pl.Save("plot"), "Expected an error saving without an extension")
+	assert.Error(t, pl.SaveAll("plot", "png", "gif"), "Expected an error saving an unsupported format")
+	assert.Error(t, pl.SaveAll("plot"), "Expected an error saving without formats")
+}
+
+func TestValidateFile(t *testing.T) {
+	pl := Plot{Fn: NewFn(math.Sqrt, 10, Float64Range(0, 100)), X: &StdAxix{}, Y: &StdAxix{}}
+	tests := []struct {
+		description string
+		pl          Plot
+		filename    string
+		err         string
+	}{
+		{description: "supported extension", pl: pl, filename: "plot.png"},
+		{description: "uppercase extension", pl: pl, filename: "plot.SVG"},
+		{description: "unsupported extension", pl: pl, filename: "plot.gif", err: "unsupported file extension"},
+		{description: "no extension", pl: pl, filename: "plot", err: "has no extension"},
+		{description: "invalid plot", pl: Plot{Fn: pl.Fn}, filename: "plot.png", err: "plot has no X axis"},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			err := test.pl.ValidateFile(test.filename)
+			if test.err == "" {
+				assert.NoError(t, err, "Expected the plot to be valid")
+				return
+			}
+			require.Error(t, err, "Expected the plot to be invalid")
+			assert.Contains(t, err.Error(), test.err, "Expected the error message")
+		})
+	}
+}
+
+func TestSaveAll(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fnplot")
+	require.NoError(t, err, "Error creating temporary directory")
+	defer os.RemoveAll(dir)
+
+	pl := Plot{Fn: NewFn(math.Sqrt, 10, Float64Range(0, 100)), X: &StdAxix{}, Y: &StdAxix{}}
+	base := filepath.Join(dir, "sqrt")
+	require.NoError(t, pl.SaveAll(base, "png", "svg", "pdf"), "Error saving plot")
+	for _, ext := range []string{"png", "svg", "pdf"} {
+		info, err := os.Stat(base + "." + ext)
+		require.NoError(t, err, "Expected the "+ext+" image")
+		assert.NotZero(t, info.Size(), "Expected the "+ext+" image to not be empty")
+	}
+}
+
+func TestPlotWith(t *testing.T) {
+	var calls int64
+	fn := func(x float64) float64 {
+		atomic.AddInt64(&calls, 1)
+		return x * x
+	}
+	pl, err := NewPlot(
+		WithFunc(fn, Float64Range(1, 100)),
+		WithSamples(20),
+		WithAxes(&StdAxix{}, &StdAxix{}),
+	)
+	require.NoError(t, err, "Error creating plot")
+	ln, err := pl.With(WithAxes(&LnAxis{}, &LnAxis{}), WithSeries("Again", pl.Fn))
+	require.NoError(t, err, "Error creating plot with ln axes")
+
+	var buf bytes.Buffer
+	require.NoError(t, pl.Write(&buf, "svg"), "Error writing plot")
+	require.NoError(t, ln.Write(&buf, "svg"), "Error writing plot with ln axes")
+	assert.Equal(t, int64(20), atomic.LoadInt64(&calls), "Expected the function to be sampled once")
+	assert.IsType(t, &StdAxix{}, pl.X, "Expected the axes of the plot to be unchanged")
+	assert.Empty(t, pl.Series, "Expected the series of the plot to be unchanged")
+	assert.Len(t, ln.Series, 1, "Expected the added series")
+}
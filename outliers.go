@@ -0,0 +1,80 @@
+package fnplot
+
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// An OutlierMethod determines how outlying output values are identified.
+type OutlierMethod int
+
+const (
+	// IQR identifies outputs more than threshold times the interquartile
+	// range below the first quartile or above the third quartile. The default
+	// threshold is 1.5.
+	IQR OutlierMethod = iota
+	// ZScore identifies outputs more than threshold standard deviations from
+	// the mean. The default threshold is 3.
+	ZScore
+)
+
+// TrimOutliers splits the set into the pairs with typical output values and
+// the pairs with outlying output values, identified with the given method. If
+// threshold is zero, the default threshold of the method is used. Plot the kept
+// pairs with FnOf and report the outliers separately.
+func (set *ValuesSet) TrimOutliers(method OutlierMethod, threshold float64) (kept, outliers *ValuesSet, err error) {
+	_, outputs, err := set.scalars()
+	if err != nil {
+		return nil, nil, err
+	}
+	low, high, err := outlierBounds(method, threshold, outputs)
+	if err != nil {
+		return nil, nil, err
+	}
+	isOutlier := func(s Sample) bool {
+		if low == nil {
+			return false
+		}
+		return s.OutputScalar.Cmp(low) == -1 || s.OutputScalar.Cmp(high) == 1
+	}
+	kept, err = set.filter(func(s Sample) bool { return !isOutlier(s) })
+	if err != nil {
+		return nil, nil, err
+	}
+	outliers, err = set.filter(isOutlier)
+	if err != nil {
+		return nil, nil, err
+	}
+	return kept, outliers, nil
+}
+
+// outlierBounds returns the smallest and largest values that aren't outliers,
+// or nil if none of the values are outliers.
+func outlierBounds(method OutlierMethod, threshold float64, values []*big.Float) (low, high *big.Float, err error) {
+	if len(values) == 0 {
+		return nil, nil, nil
+	}
+	switch method {
+	case IQR:
+		if threshold == 0 {
+			threshold = 1.5
+		}
+		sorted := sortedFloats(values)
+		q1, q3 := nearestRank(sorted, 25), nearestRank(sorted, 75)
+		margin := newFloat().Sub(q3, q1)
+		margin.Mul(margin, big.NewFloat(threshold))
+		return newFloat().Sub(q1, margin), newFloat().Add(q3, margin), nil
+	case ZScore:
+		if threshold == 0 {
+			threshold = 3
+		}
+		s := summarize(values, nil)
+		if s.StdDev.Sign() == 0 {
+			return nil, nil, nil
+		}
+		margin := newFloat().Mul(s.StdDev, big.NewFloat(threshold))
+		return newFloat().Sub(s.Mean, margin), newFloat().Add(s.Mean, margin), nil
+	}
+	return nil, nil, errors.Errorf("unknown outlier method %d", method)
+}
@@ -0,0 +1,41 @@
+package fnplot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrimOutliers(t *testing.T) {
+	outputs := []float64{10, 11, 9, 10, 12, 10, 9, 11, 10, 100}
+	inputs := make([]float64, len(outputs))
+	for i := range inputs {
+		inputs[i] = float64(i)
+	}
+	set := newTestSet(t, inputs, outputs)
+
+	tests := []struct {
+		description      string
+		method           OutlierMethod
+		threshold        float64
+		expectedOutliers []float64
+	}{
+		{description: "IQR", method: IQR, expectedOutliers: []float64{100}},
+		{description: "z-score", method: ZScore, threshold: 2, expectedOutliers: []float64{100}},
+		{description: "z-score default threshold", method: ZScore, expectedOutliers: []float64{}},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			kept, outliers, err := set.TrimOutliers(test.method, test.threshold)
+			require.NoError(t, err, "Error trimming outliers")
+			_, actualOutliers := setFloats(t, outliers)
+			assert.Equal(t, test.expectedOutliers, actualOutliers, "Expected and actual outliers are different")
+			assert.Equal(t, len(outputs)-len(test.expectedOutliers), kept.Len(), "Expected and actual number of kept pairs are different")
+		})
+	}
+
+	_, _, err := set.TrimOutliers(OutlierMethod(-1), 0)
+	assert.Error(t, err, "Expected an error for an unknown outlier method")
+}
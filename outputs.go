@@ -0,0 +1,52 @@
+package fnplot
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Output returns a new set with only the output value at index i of each pair
+// in the set, converted to its own scalar value with the output strategy of
+// the set. Functions with more than one return value, like the number of
+// comparisons and swaps of a sort, have all of their return values converted
+// to a single scalar value unless they're split with Output.
+func (set *ValuesSet) Output(i int) (*ValuesSet, error) {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	samples, err := set.samplesLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ValuesSet{config: set.config}
+	for j, s := range samples {
+		if i < 0 || i >= len(s.Output) {
+			return nil, fmt.Errorf("output %d has no value %d", j, i)
+		}
+		s.Output = Values{s.Output[i]}
+		s.OutputScalar, err = set.config.output(s.Output)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("error converting value %d of output %d to int", i, j))
+		}
+		if err := output.insertSample(s); err != nil {
+			return nil, err
+		}
+	}
+	return output, nil
+}
+
+// SplitOutputs returns a series for each return value of the function, named
+// by the given names in the same order as the return values. Add the series
+// to a Plot to draw each return value as a separate line on the same chart.
+func (fn Fn) SplitOutputs(names ...string) ([]Series, error) {
+	series := make([]Series, len(names))
+	for i := range names {
+		set, err := fn.set.Output(i)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error splitting output "+names[i])
+		}
+		series[i] = Series{Name: names[i], Fn: FnOf(set)}
+	}
+	return series, nil
+}
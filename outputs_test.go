@@ -0,0 +1,41 @@
+package fnplot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitOutputs(t *testing.T) {
+	fn := NewFn(func(x float64) (float64, float64) { return x, x * x }, 50, Float64Range(0, 100))
+	series, err := fn.SplitOutputs("linear", "square")
+	require.NoError(t, err, "Error splitting outputs")
+	require.Len(t, series, 2, "Expected and actual number of series are different")
+	assert.Equal(t, "linear", series[0].Name, "Expected and actual series names are different")
+
+	inputs, linear := setFloats(t, series[0].Fn.ValuesSet())
+	_, square := setFloats(t, series[1].Fn.ValuesSet())
+	require.Len(t, inputs, 50, "Expected and actual number of samples are different")
+	for i := range inputs {
+		assert.Equal(t, inputs[i], linear[i], "Expected and actual linear outputs are different")
+		assert.Equal(t, inputs[i]*inputs[i], square[i], "Expected and actual square outputs are different")
+	}
+
+	_, err = fn.SplitOutputs("linear", "square", "cube")
+	assert.Error(t, err, "Expected an error for a missing output")
+}
+
+func TestOutputScalarConfig(t *testing.T) {
+	set := NewValuesSetWithConfig(nil, ScalarConfig{Output: ByLength})
+	require.NoError(t, set.insert(NewValues(1), NewValues("abc", "de")), "Error inserting values")
+
+	output, err := set.Output(1)
+	require.NoError(t, err, "Error splitting output")
+	_, outputs := setFloats(t, output)
+	assert.Equal(t, []float64{2}, outputs, "Expected the output to be converted with the ByLength strategy of the set")
+
+	require.NoError(t, output.insert(NewValues(2), NewValues("wxyz")), "Error inserting values")
+	_, outputs = setFloats(t, output)
+	assert.Equal(t, []float64{2, 4}, outputs, "Expected the split set to keep the strategies of the set")
+}
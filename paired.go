@@ -0,0 +1,111 @@
+package fnplot
+
+import (
+	"math/big"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// A Comparison is how Paired compares the outputs of two functions.
+type Comparison int
+
+const (
+	// Difference is the output of the second function minus the output of
+	// the first function.
+	Difference Comparison = iota
+	// Ratio is the output of the second function divided by the output of
+	// the first function.
+	Ratio
+)
+
+// Paired is the comparison of two functions sampled with identical inputs.
+type Paired struct {
+	// A and B are the outputs of each function.
+	A, B Fn
+	// Comparison is the difference or ratio of the outputs of the functions
+	// for each input. Run it to sample both functions more.
+	Comparison Fn
+}
+
+var bigFloatType = reflect.TypeOf((*big.Float)(nil))
+
+// NewPaired samples two functions with the same parameters, feeding the same
+// generated inputs to both, and compares their outputs for each input. The
+// comparison of outputs for identical inputs is far more sensitive to small
+// differences, like the speedup of an optimization measured with Measure, than
+// overlaying two independently sampled series. The comparison of inputs that
+// either function returns an error for, or that are divided by zero, are
+// recorded as Failures of the Comparison set.
+func NewPaired(a, b interface{}, samples int, comparison Comparison, gens ...Generator) (Paired, error) {
+	aVal, bVal := reflect.ValueOf(a), reflect.ValueOf(b)
+	if aVal.Kind() != reflect.Func || bVal.Kind() != reflect.Func {
+		return Paired{}, errors.New("paired functions must be funcs")
+	}
+	aType, bType := aVal.Type(), bVal.Type()
+	if aType.NumIn() != bType.NumIn() {
+		return Paired{}, errors.Errorf("paired functions have different parameters: %s and %s", aType, bType)
+	}
+	in := make([]reflect.Type, aType.NumIn())
+	for i := range in {
+		if aType.In(i) != bType.In(i) {
+			return Paired{}, errors.Errorf("paired functions have different parameters: %s and %s", aType, bType)
+		}
+		in[i] = aType.In(i)
+	}
+
+	aSet := NewValuesSet(NewMemoryStore(0))
+	bSet := NewValuesSet(NewMemoryStore(0))
+	compareType := reflect.FuncOf(in, []reflect.Type{bigFloatType, errorType}, aType.IsVariadic())
+	compare := reflect.MakeFunc(compareType, func(args []reflect.Value) []reflect.Value {
+		aOut, err := pairedOutput(aSet, aVal, args)
+		if err != nil {
+			return []reflect.Value{reflect.Zero(bigFloatType), reflect.ValueOf(&err).Elem()}
+		}
+		bOut, err := pairedOutput(bSet, bVal, args)
+		if err == nil {
+			var compared *big.Float
+			compared, err = compare(comparison, aOut, bOut)
+			if err == nil {
+				return []reflect.Value{reflect.ValueOf(compared), reflect.Zero(errorType)}
+			}
+		}
+		return []reflect.Value{reflect.Zero(bigFloatType), reflect.ValueOf(&err).Elem()}
+	})
+
+	set := NewValuesSet(NewMemoryStore(0))
+	return Paired{
+		A:          FnOf(aSet),
+		B:          FnOf(bSet),
+		Comparison: NewFnWithSet(compare.Interface(), samples, set, gens...),
+	}, nil
+}
+
+// pairedOutput calls the function with the arguments, inserts the pair into
+// the set, and returns the output scalar value.
+func pairedOutput(set *ValuesSet, fnVal reflect.Value, args []reflect.Value) (*big.Float, error) {
+	results, labels, err := splitResults(fnVal.Call(args))
+	if err != nil {
+		return nil, err
+	}
+	output := Values(results)
+	if err := set.insertLabeled(Values(args), output, labels); err != nil {
+		return nil, err
+	}
+	out, err := set.config.output(output)
+	return out, errors.WithMessage(err, "error converting output")
+}
+
+// compare returns the difference or ratio of the outputs.
+func compare(comparison Comparison, a, b *big.Float) (*big.Float, error) {
+	switch comparison {
+	case Difference:
+		return newFloat().Sub(b, a), nil
+	case Ratio:
+		if a.Sign() == 0 {
+			return nil, errors.New("ratio of outputs divided by zero")
+		}
+		return newFloat().Quo(b, a), nil
+	}
+	return nil, errors.Errorf("unknown comparison %d", comparison)
+}
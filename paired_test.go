@@ -0,0 +1,47 @@
+package fnplot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPaired(t *testing.T) {
+	double := func(f float64) float64 { return 2 * f }
+	triple := func(f float64) float64 { return 3 * f }
+
+	tests := []struct {
+		description string
+		comparison  Comparison
+		expected    func(x float64) float64
+	}{
+		{description: "difference", comparison: Difference, expected: func(x float64) float64 { return x }},
+		{description: "ratio", comparison: Ratio, expected: func(float64) float64 { return 1.5 }},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			paired, err := NewPaired(double, triple, 50, test.comparison, Float64Range(1, 10))
+			require.NoError(t, err, "Error comparing functions")
+			inputs, outputs := setFloats(t, paired.Comparison.ValuesSet())
+			require.Len(t, inputs, 50, "Expected a comparison for each input")
+			for i := range inputs {
+				assert.InDelta(t, test.expected(inputs[i]), outputs[i], 1e-9, "Expected the compared outputs")
+			}
+			aInputs, aOutputs := setFloats(t, paired.A.ValuesSet())
+			bInputs, bOutputs := setFloats(t, paired.B.ValuesSet())
+			assert.ElementsMatch(t, aInputs, bInputs, "Expected both functions to get the same inputs")
+			assert.Len(t, aOutputs, 50, "Expected the outputs of the first function")
+			assert.Len(t, bOutputs, 50, "Expected the outputs of the second function")
+		})
+	}
+
+	paired, err := NewPaired(func(int) int { return 0 }, func(int) int { return 1 }, 10, Ratio, IntRange(0, 10))
+	require.NoError(t, err, "Error comparing functions")
+	assert.Equal(t, 0, paired.Comparison.ValuesSet().Len(), "Expected no ratios of zero outputs")
+	assert.Len(t, paired.Comparison.ValuesSet().Failures(), 10, "Expected a failure for each ratio of a zero output")
+
+	_, err = NewPaired(double, func(i int) int { return i }, 10, Difference)
+	assert.Error(t, err, "Expected an error comparing functions with different parameters")
+}
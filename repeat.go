@@ -0,0 +1,135 @@
+package fnplot
+
+import (
+	"image/color"
+	"math/big"
+	"sort"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+)
+
+// A Repeat summarizes the outputs of the pairs of a set with the same input,
+// like the repeated calls of RunConfig.Repeat.
+type Repeat struct {
+	Input       Values
+	InputScalar *big.Float
+	Output      Summary
+}
+
+// Repeats groups the pairs of the set by their input scalar value and
+// summarizes the output scalar values of each group, in the order of the input
+// scalar values.
+func (set *ValuesSet) Repeats() ([]Repeat, error) {
+	samples, err := set.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := sampleScalars(samples); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].InputScalar.Cmp(samples[j].InputScalar) == -1
+	})
+
+	var repeats []Repeat
+	for i := 0; i < len(samples); {
+		j := i
+		var outputs []*big.Float
+		for ; j < len(samples) && samples[j].InputScalar.Cmp(samples[i].InputScalar) == 0; j++ {
+			outputs = append(outputs, samples[j].OutputScalar)
+		}
+		repeats = append(repeats, Repeat{
+			Input:       samples[i].Input,
+			InputScalar: samples[i].InputScalar,
+			Output:      summarize(outputs, nil),
+		})
+		i = j
+	}
+	return repeats, nil
+}
+
+// groupPoints groups the points, which must be sorted by X, by their X value.
+// The Y values of each group are sorted.
+func groupPoints(points plotter.XYs) []bin {
+	var groups []bin
+	for _, p := range points {
+		if len(groups) == 0 || groups[len(groups)-1].x != p.X {
+			groups = append(groups, bin{x: p.X})
+		}
+		g := &groups[len(groups)-1]
+		g.ys = append(g.ys, p.Y)
+	}
+	for i := range groups {
+		sort.Float64s(groups[i].ys)
+	}
+	return groups
+}
+
+// repeatErrors are the medians of groups of points with the smallest and
+// largest Y value of each group as the error.
+type repeatErrors struct {
+	plotter.XYs
+	plotter.YErrors
+}
+
+// addRepeats draws each series with the ErrorBars or Band style.
+func addRepeats(p *plot.Plot, style PlotStyle, styles []SeriesStyle, names []string, points []plotter.XYs) error {
+	for i := range points {
+		groups := groupPoints(points[i])
+		if len(groups) == 0 {
+			continue
+		}
+		errs := repeatErrors{
+			XYs:     make(plotter.XYs, len(groups)),
+			YErrors: make(plotter.YErrors, len(groups)),
+		}
+		for j, g := range groups {
+			median := percentile(g.ys, 50)
+			errs.XYs[j] = plotter.XY{X: g.x, Y: median}
+			errs.YErrors[j].Low = median - g.ys[0]
+			errs.YErrors[j].High = g.ys[len(g.ys)-1] - median
+		}
+
+		var thumbnails []plot.Thumbnailer
+		switch style {
+		case ErrorBars:
+			bars, err := plotter.NewYErrorBars(errs)
+			if err != nil {
+				return errors.WithMessage(err, "error creating error bars for series "+names[i])
+			}
+			bars.LineStyle.Color = styles[i].Color
+			p.Add(bars)
+		case Band:
+			ring := make(plotter.XYs, 0, 2*len(groups))
+			for _, g := range groups {
+				ring = append(ring, plotter.XY{X: g.x, Y: g.ys[0]})
+			}
+			for j := len(groups) - 1; j >= 0; j-- {
+				ring = append(ring, plotter.XY{X: groups[j].x, Y: groups[j].ys[len(groups[j].ys)-1]})
+			}
+			band, err := plotter.NewPolygon(ring)
+			if err != nil {
+				return errors.WithMessage(err, "error creating band for series "+names[i])
+			}
+			r, g, b, _ := styles[i].Color.RGBA()
+			band.Color = color.NRGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: 0x40}
+			band.LineStyle.Width = 0
+			p.Add(band)
+			thumbnails = append(thumbnails, band)
+		}
+
+		line, err := plotter.NewLine(errs.XYs)
+		if err != nil {
+			return err
+		}
+		line.LineStyle = styles[i].lineStyle(line.LineStyle)
+		p.Add(line)
+		thumbnails = append(thumbnails, line)
+		if names[i] != "" {
+			p.Legend.Add(names[i], thumbnails...)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package fnplot
+
+import (
+	"math/big"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunWithConfigRepeat(t *testing.T) {
+	var calls int64
+	fn := newFn(func(i int) int {
+		return i*10 + int(atomic.AddInt64(&calls, 1)-1)%3
+	}, NewValuesSet(NewMemoryStore(0)), Sequence(1, 2, 1))
+	require.NoError(t, fn.RunWithConfig(2, RunConfig{Workers: 1, Repeat: 3}), "Error running function")
+	assert.Equal(t, 6, fn.ValuesSet().Len(), "Expected a pair for each call")
+
+	repeats, err := fn.ValuesSet().Repeats()
+	require.NoError(t, err, "Error summarizing repeats")
+	require.Len(t, repeats, 2, "Expected a summary for each input")
+	for i, r := range repeats {
+		base := float64(10 * (i + 1))
+		assert.Equal(t, NewValues(i+1).String(), r.Input.String(), "Expected the input")
+		assert.Equal(t, 3, r.Output.Count, "Expected the outputs of each call")
+		assert.Equal(t, big.NewFloat(base), r.Output.Min, "Expected the smallest output")
+		assert.Equal(t, big.NewFloat(base+1), r.Output.Median, "Expected the median output")
+		assert.Equal(t, big.NewFloat(base+2), r.Output.Max, "Expected the largest output")
+	}
+
+	for _, style := range []PlotStyle{ErrorBars, Band} {
+		pl := Plot{Fn: fn, X: &StdAxix{}, Y: &StdAxix{}, Style: style}
+		p, err := pl.render()
+		require.NoError(t, err, "Error rendering plot")
+		assert.Equal(t, 10.0, p.Y.Min, "Expected the Y axis to include the smallest outputs")
+		assert.Equal(t, 22.0, p.Y.Max, "Expected the Y axis to include the largest outputs")
+	}
+}
@@ -0,0 +1,474 @@
+package fnplot
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leanovate/gopter"
+	"github.com/pkg/errors"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// defaultWorkers is the number of workers that sample a function concurrently
+// if RunConfig.Workers is zero.
+const defaultWorkers = 10
+
+// A RunConfig controls how the inputs of a Fn are generated. The zero
+// RunConfig samples with 10 workers and a seed based on the current time.
+type RunConfig struct {
+	// Seed is the seed of the generated inputs. If zero, a seed based on the
+	// current time is used. The same seed only generates the same inputs with
+	// a single worker.
+	Seed int64
+	// Workers is the number of workers that sample the function concurrently.
+	// If zero, 10 workers are used.
+	Workers int
+	// MinSize and MaxSize bound the sizes of generated collections, like the
+	// slices generated by SliceOf, which grow from MinSize to MaxSize over the
+	// run. If MaxSize is zero, the number of samples is used.
+	MinSize, MaxSize int
+	// MaxShrinkCount is the most times gopter shrinks the inputs of a sample
+	// that fails.
+	MaxShrinkCount int
+	// MaxDiscardRatio is the most generated inputs that can be discarded, like
+	// the inputs that a generator with SuchThat doesn't generate, for each
+	// sample before the run stops.
+	MaxDiscardRatio float64
+	// OnProgress, if not nil, is called with the number of samples done so
+	// far and the total number of samples of the run after each sample is
+	// done, like for showing a progress bar. Calls are serialized, so done
+	// always increases, but the function shouldn't block for long since the
+	// workers wait for it.
+	OnProgress func(done, total int)
+	// RecoverPanics recovers the panics of the function, recording the inputs
+	// that panicked in the ValuesSet (see ValuesSet.Failures) and sampling
+	// more inputs. Otherwise a panic of the function stops the run and is
+	// raised again by the run.
+	RecoverPanics bool
+	// Timeout, if more than zero, is the most time that the function can take
+	// for an input. Inputs that the function doesn't return for in time are
+	// recorded in the ValuesSet as Failures with ErrTimeout (see
+	// ValuesSet.Failures), and sampling continues. The function can't be
+	// stopped, so it keeps running in the background.
+	Timeout time.Duration
+	// Repeat, if more than one, is the number of times the function is called
+	// with each generated input, inserting a pair for each call, like for
+	// measuring noisy timings. Summarize the outputs of each input with
+	// ValuesSet.Repeats, or plot their spread with the ErrorBars or Band
+	// styles.
+	Repeat int
+}
+
+// testParameters returns the gopter test parameters that run the given number
+// of samples, generating the inputs from the given seed.
+func (rc RunConfig) testParameters(samples int, seed int64) *gopter.TestParameters {
+	workers := rc.Workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+	maxSize := rc.MaxSize
+	if maxSize == 0 {
+		maxSize = samples
+	}
+	return &gopter.TestParameters{
+		MinSuccessfulTests: samples,
+		MinSize:            rc.MinSize,
+		MaxSize:            maxSize,
+		MaxShrinkCount:     rc.MaxShrinkCount,
+		MaxDiscardRatio:    rc.MaxDiscardRatio,
+		Seed:               seed,
+		Rng:                rand.New(gopter.NewLockedSource(seed)),
+		Workers:            workers,
+	}
+}
+
+// RunWithConfig samples the function samples more times with the given config,
+// inserting the input/output pairs into its ValuesSet.
+func (fn Fn) RunWithConfig(samples int, config RunConfig) error {
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	if config.Repeat > 1 {
+		fn = fn.withRepeat(config.Repeat)
+	}
+	if config.Timeout > 0 {
+		fn = fn.withCaller(timeoutCaller(fn.call, config.Timeout))
+	}
+	if config.RecoverPanics {
+		fn.p = recoverProp(fn.p, fn.set)
+	}
+	if config.OnProgress != nil {
+		fn.p = progressProp(fn.p, samples, config.OnProgress)
+	}
+	fn.set.mu.Lock()
+	fn.set.runConfig = config
+	fn.set.mu.Unlock()
+	return fn.check(config.testParameters(samples, seed))
+}
+
+// A Failure is an input that the function returned an error for or panicked
+// for.
+type Failure struct {
+	Input Values
+	// Err is the error that the function returned, if it returned one.
+	Err error
+	// Panic is the value that the function panicked with, if it panicked.
+	Panic interface{}
+	// Stack is the stack trace of the panic.
+	Stack []byte
+}
+
+func (f *Failure) Error() string {
+	if f.Err == ErrTimeout {
+		return fmt.Sprintf("function timed out for input %s", f.Input)
+	}
+	if f.Err != nil {
+		return fmt.Sprintf("function returned an error for input %s: %s", f.Input, f.Err)
+	}
+	return fmt.Sprintf("function panicked for input %s: %v", f.Input, f.Panic)
+}
+
+// ErrTimeout is the error of the Failures of inputs that the function didn't
+// return for within RunConfig.Timeout.
+var ErrTimeout = errors.New("function timed out")
+
+// A caller calls a sampled function with the arguments, returning a panic of
+// the function, or an input that can't be sampled, as a Failure.
+type caller func(fnVal reflect.Value, args []reflect.Value) ([]reflect.Value, *Failure)
+
+// callRecovered calls the function with the arguments, returning a panic of
+// the function as a Failure.
+func callRecovered(fnVal reflect.Value, args []reflect.Value) (results []reflect.Value, failure *Failure) {
+	defer func() {
+		if r := recover(); r != nil {
+			failure = &Failure{Input: Values(args), Panic: r, Stack: debug.Stack()}
+		}
+	}()
+	return fnVal.Call(args), nil
+}
+
+// timeoutCaller returns a caller that records a Failure with ErrTimeout for the
+// inputs that the function doesn't return for within the timeout. The function
+// can't be stopped, so it keeps running in the background.
+func timeoutCaller(call caller, timeout time.Duration) caller {
+	return func(fnVal reflect.Value, args []reflect.Value) ([]reflect.Value, *Failure) {
+		type called struct {
+			results []reflect.Value
+			failure *Failure
+		}
+		done := make(chan called, 1)
+		go func() {
+			results, failure := call(fnVal, args)
+			done <- called{results: results, failure: failure}
+		}()
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case c := <-done:
+			return c.results, c.failure
+		case <-timer.C:
+			return nil, &Failure{Input: Values(args), Err: ErrTimeout}
+		}
+	}
+}
+
+// sample calls the function with the arguments using call and inserts the
+// input/output pair into the set, or records a Failure if the function returns
+// an error or can't be sampled. If the function panics, the panic is returned
+// as a Failure.
+func (set *ValuesSet) sample(call caller, fnVal reflect.Value, args []reflect.Value) *Failure {
+	called, failure := call(fnVal, args)
+	if failure != nil && failure.Panic == nil {
+		set.addFailure(*failure)
+		return nil
+	}
+	if failure != nil {
+		return failure
+	}
+	results, labels, err := splitResults(called)
+	if err != nil {
+		set.addFailure(Failure{Input: Values(args), Err: err})
+		return nil
+	}
+	set.insertLabeled(args, results, labels)
+	return nil
+}
+
+// recoverProp returns a gopter.Prop that records the Failures of p in the set
+// as done samples.
+func recoverProp(p gopter.Prop, set *ValuesSet) gopter.Prop {
+	return func(genParams *gopter.GenParameters) *gopter.PropResult {
+		result := p(genParams)
+		if failure, ok := result.Error.(*Failure); ok {
+			set.addFailure(*failure)
+			return &gopter.PropResult{Status: gopter.PropTrue}
+		}
+		return result
+	}
+}
+
+// errorType is the type of the error that a sampled function can return as its
+// last return value.
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// splitError removes an error last return value from the results of a sampled
+// function and returns the remaining results and the error.
+func splitError(results []reflect.Value) ([]reflect.Value, error) {
+	if len(results) == 0 || results[len(results)-1].Type() != errorType {
+		return results, nil
+	}
+	last := results[len(results)-1]
+	if last.IsNil() {
+		return results[:len(results)-1], nil
+	}
+	return results[:len(results)-1], last.Interface().(error)
+}
+
+// splitResults removes a Tag or Labels and an error last return value, in
+// either order, from the results of a sampled function and returns the
+// remaining results, the labels, and the error.
+func splitResults(results []reflect.Value) ([]reflect.Value, Labels, error) {
+	n := len(results)
+	results, err := splitError(results)
+	results, labels := splitLabels(results)
+	if len(results) == n-1 && labels != nil {
+		results, err = splitError(results)
+	}
+	return results, labels, err
+}
+
+// addFailure records the failure in the set.
+func (set *ValuesSet) addFailure(failure Failure) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.failures = append(set.failures, failure)
+}
+
+// Failures returns the inputs that the function returned an error for, which
+// aren't inserted as samples, and the inputs that the function panicked for in
+// runs that recover panics (see RunConfig.RecoverPanics), in the order they
+// were sampled.
+func (set *ValuesSet) Failures() []Failure {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	failures := make([]Failure, len(set.failures))
+	copy(failures, set.failures)
+	return failures
+}
+
+// progressProp returns a gopter.Prop that calls onProgress after each sample of
+// p is done.
+func progressProp(p gopter.Prop, total int, onProgress func(done, total int)) gopter.Prop {
+	var mu sync.Mutex
+	var done int
+	return func(genParams *gopter.GenParameters) *gopter.PropResult {
+		result := p(genParams)
+		if result.Status == gopter.PropUndecided {
+			return result
+		}
+		mu.Lock()
+		done++
+		onProgress(done, total)
+		mu.Unlock()
+		return result
+	}
+}
+
+// NewFnWithConfig is like NewFn, but samples the function with the given
+// config.
+func NewFnWithConfig(fn interface{}, samples int, config RunConfig, gens ...Generator) Fn {
+	f := newFn(fn, NewValuesSet(NewMemoryStore(0)), gens...)
+	f.RunWithConfig(samples, config)
+	return f
+}
+
+// RunWithSeed samples the function samples more times, generating the inputs
+// from the given seed with a single worker so that the same seed always
+// generates the same inputs. The seed of every run is recorded in the
+// ValuesSet of the function (see ValuesSet.Seeds).
+func (fn Fn) RunWithSeed(seed int64, samples int) error {
+	return fn.runSeeded(samples, seed, 1)
+}
+
+// Seeds returns the seeds of the runs that sampled the set, in the order they
+// were run.
+func (set *ValuesSet) Seeds() []int64 {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	seeds := make([]int64, len(set.seeds))
+	copy(seeds, set.seeds)
+	return seeds
+}
+
+// seedsCaption returns a line with the seeds of the runs of each series.
+func (pl Plot) seedsCaption() string {
+	var lines []string
+	for _, s := range pl.series() {
+		seeds := s.Fn.ValuesSet().Seeds()
+		if len(seeds) == 0 {
+			continue
+		}
+		formatted := make([]string, len(seeds))
+		for i := range seeds {
+			formatted[i] = strconv.FormatInt(seeds[i], 10)
+		}
+		lines = append(lines, s.Name+" seeds: "+strings.Join(formatted, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RunContext samples the function samples more times, stopping early if the
+// context is cancelled or its deadline passes. The ValuesSet of the function is
+// returned with the pairs sampled so far even if the run stops early, along
+// with the error of the context.
+func (fn Fn) RunContext(ctx context.Context, samples int) (*ValuesSet, error) {
+	p := fn.p
+	fn.p = func(genParams *gopter.GenParameters) *gopter.PropResult {
+		if err := ctx.Err(); err != nil {
+			return &gopter.PropResult{Status: gopter.PropError, Error: err}
+		}
+		return p(genParams)
+	}
+	err := fn.RunWithConfig(samples, RunConfig{})
+	return fn.set, err
+}
+
+// failureInputs returns the scalar values of the inputs of the failures of the
+// set that keep returns true for, converted with the Input strategy of the
+// plot. Inputs that can't be converted are skipped.
+func (pl Plot) failureInputs(set *ValuesSet, keep func(f Failure) bool) []*big.Float {
+	config := set.config
+	if pl.Scalars.Input != nil {
+		config.Input = pl.Scalars.Input
+	}
+	var inputs []*big.Float
+	for _, failure := range set.Failures() {
+		if !keep(failure) {
+			continue
+		}
+		if f, err := config.input(failure.Input); err == nil {
+			inputs = append(inputs, f)
+		}
+	}
+	return inputs
+}
+
+// isTimeout returns true if the failure is a timeout.
+func isTimeout(f Failure) bool {
+	return f.Err == ErrTimeout
+}
+
+// anyFailure returns true for every failure.
+func anyFailure(Failure) bool {
+	return true
+}
+
+// addFailures draws the timeouts of every series as orange rings, and the other
+// failures as red crosses.
+func (pl Plot) addFailures(p *plot.Plot, series []Series) error {
+	y := pl.Y.Point(newFloat())
+	if math.IsNaN(y) || math.IsInf(y, 0) {
+		y = p.Y.Min
+	}
+	isError := func(f Failure) bool { return !isTimeout(f) }
+	for _, s := range series {
+		set := s.Fn.ValuesSet()
+		err := addFailurePoints(p, pl.X, y, s.Name+" failures", pl.failureInputs(set, isError),
+			draw.CrossGlyph{}, color.RGBA{R: 255, A: 255})
+		if err != nil {
+			return errors.WithMessage(err, "error plotting failures of series "+s.Name)
+		}
+		err = addFailurePoints(p, pl.X, y, s.Name+" timeouts", pl.failureInputs(set, isTimeout),
+			draw.RingGlyph{}, color.RGBA{R: 255, G: 165, A: 255})
+		if err != nil {
+			return errors.WithMessage(err, "error plotting timeouts of series "+s.Name)
+		}
+	}
+	return nil
+}
+
+// addFailurePoints draws the failure inputs at y with the given glyph.
+func addFailurePoints(p *plot.Plot, xAxis Axis, y float64, name string, inputs []*big.Float, shape draw.GlyphDrawer, c color.Color) error {
+	if len(inputs) == 0 {
+		return nil
+	}
+	points := make(plotter.XYs, len(inputs))
+	for i := range inputs {
+		points[i].X = xAxis.Point(inputs[i])
+		points[i].Y = y
+	}
+	scatter, err := plotter.NewScatter(points)
+	if err != nil {
+		return err
+	}
+	scatter.GlyphStyle.Shape = shape
+	scatter.GlyphStyle.Color = c
+	p.Add(scatter)
+	p.Legend.Add(name, scatter)
+	return nil
+}
+
+// Replay samples the function again with exactly the inputs of the pairs in
+// set, in the order they're stored, inserting the new input/output pairs into
+// the ValuesSet of the function. Replay the set of an earlier run to compare
+// the function before and after a change on identical inputs. The inputs must
+// be assignable to the parameters of the function, so sets read with ReadGob or
+// LoadJSON, which keep their values as strings, can only be replayed by
+// functions of string parameters. The function is called with the RecoverPanics
+// and Timeout of the run that sampled set, and a panic that isn't recovered
+// stops the replay and is returned as a *Failure.
+func (fn Fn) Replay(set *ValuesSet) error {
+	if !fn.fn.IsValid() {
+		return errors.New("Fn has no function to run")
+	}
+	fnType := fn.fn.Type()
+	var inputs []Values
+	err := set.Each(func(input, _ Values, _, _ *big.Float) bool {
+		inputs = append(inputs, input)
+		return true
+	})
+	if err != nil {
+		return errors.WithMessage(err, "error reading inputs to replay")
+	}
+	for _, input := range inputs {
+		if len(input) != fnType.NumIn() {
+			return errors.Errorf("input %s has %d values, expected %d", input, len(input), fnType.NumIn())
+		}
+		for i := range input {
+			if !input[i].IsValid() || !input[i].Type().AssignableTo(fnType.In(i)) {
+				return errors.Errorf("value %d of input %s isn't assignable to %s", i, input, fnType.In(i))
+			}
+		}
+	}
+	set.mu.RLock()
+	config := set.runConfig
+	set.mu.RUnlock()
+	call := fn.call
+	if config.Timeout > 0 {
+		call = timeoutCaller(call, config.Timeout)
+	}
+	for _, input := range inputs {
+		failure := fn.set.sample(call, fn.fn, input)
+		if failure == nil {
+			continue
+		}
+		if !config.RecoverPanics {
+			return failure
+		}
+		fn.set.addFailure(*failure)
+	}
+	return nil
+}
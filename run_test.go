@@ -0,0 +1,216 @@
+package fnplot
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunWithConfig(t *testing.T) {
+	config := RunConfig{Seed: 42, Workers: 1}
+	identity := func(f float64) float64 { return f }
+	a := NewFnWithConfig(identity, 20, config, Float64Range(0, 1))
+	b := NewFnWithConfig(identity, 20, config, Float64Range(0, 1))
+	aInputs, _ := setFloats(t, a.ValuesSet())
+	bInputs, _ := setFloats(t, b.ValuesSet())
+	assert.Len(t, aInputs, 20, "Expected every sample")
+	assert.Equal(t, aInputs, bInputs, "Expected the same seed to generate the same inputs")
+
+	// Generated slices are no longer than MaxSize.
+	fn := NewFnWithConfig(func(b []bool) int { return len(b) }, 50, RunConfig{MaxSize: 5}, SliceOf(Bool()))
+	_, outputs := setFloats(t, fn.ValuesSet())
+	require.Len(t, outputs, 50, "Expected every sample")
+	for _, out := range outputs {
+		assert.True(t, out < 5, "Expected slices shorter than MaxSize")
+	}
+}
+
+func TestRunWithSeed(t *testing.T) {
+	identity := func(f float64) float64 { return f }
+	a := NewFnWithCapacity(identity, 0, 0, Float64Range(0, 1))
+	require.NoError(t, a.RunWithSeed(7, 10), "Error running function")
+	b := NewFnWithCapacity(identity, 0, 0, Float64Range(0, 1))
+	require.NoError(t, b.RunWithSeed(7, 10), "Error running function")
+	aInputs, _ := setFloats(t, a.ValuesSet())
+	bInputs, _ := setFloats(t, b.ValuesSet())
+	assert.Equal(t, aInputs, bInputs, "Expected the same seed to generate the same inputs")
+
+	seeds := a.ValuesSet().Seeds()
+	require.Len(t, seeds, 2, "Expected the seed of each run")
+	assert.Equal(t, int64(7), seeds[1], "Expected the seed of the last run")
+
+	pl := Plot{Fn: a}
+	assert.Contains(t, pl.seedsCaption(), "Fn seeds: ", "Expected the seeds in the caption")
+	assert.Contains(t, pl.seedsCaption(), ", 7", "Expected the seed of the last run in the caption")
+}
+
+func TestRunContext(t *testing.T) {
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var calls int32
+		fn := newFn(func(f float64) float64 {
+			if atomic.AddInt32(&calls, 1) == 5 {
+				cancel()
+			}
+			return f
+		}, NewValuesSet(NewMemoryStore(0)), Float64Range(0, 1))
+
+		set, err := fn.RunContext(ctx, 1000)
+		assert.Equal(t, context.Canceled, err, "Expected the context error")
+		assert.True(t, set.Len() >= 5, "Expected the pairs sampled before cancelling, got %d", set.Len())
+		assert.True(t, set.Len() < 1000, "Expected the run to stop early, got %d", set.Len())
+	})
+	t.Run("deadline passed", func(t *testing.T) {
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+		fn := newFn(func(f float64) float64 { return f }, NewValuesSet(NewMemoryStore(0)), Float64Range(0, 1))
+
+		set, err := fn.RunContext(ctx, 100)
+		assert.Equal(t, context.DeadlineExceeded, err, "Expected the context error")
+		assert.Equal(t, 0, set.Len(), "Expected no pairs")
+	})
+	t.Run("not cancelled", func(t *testing.T) {
+		fn := newFn(func(f float64) float64 { return f }, NewValuesSet(NewMemoryStore(0)), Float64Range(0, 1))
+
+		set, err := fn.RunContext(context.Background(), 100)
+		require.NoError(t, err, "Error running function")
+		assert.Equal(t, 100, set.Len(), "Expected every pair")
+	})
+}
+
+func TestRunWithConfigOnProgress(t *testing.T) {
+	var calls [][2]int
+	config := RunConfig{
+		OnProgress: func(done, total int) {
+			calls = append(calls, [2]int{done, total})
+		},
+	}
+	fn := NewFnWithConfig(func(f float64) float64 { return f }, 50, config, Float64Range(0, 1))
+	require.Equal(t, 50, fn.ValuesSet().Len(), "Expected every pair")
+	require.Len(t, calls, 50, "Expected a call after each sample")
+	for i, call := range calls {
+		assert.Equal(t, [2]int{i + 1, 50}, call, "Expected the samples done and total")
+	}
+}
+
+func TestRunWithConfigRecoverPanics(t *testing.T) {
+	fn := newFn(func(i int) int {
+		if i%2 == 1 {
+			panic("odd input")
+		}
+		return i
+	}, NewValuesSet(NewMemoryStore(0)), Sequence(0, 100, 1))
+
+	err := fn.RunWithConfig(20, RunConfig{Workers: 1, RecoverPanics: true})
+	require.NoError(t, err, "Error running function")
+	set := fn.ValuesSet()
+	assert.Equal(t, 10, set.Len(), "Expected the pairs of the inputs that didn't panic")
+	failures := set.Failures()
+	require.Len(t, failures, 10, "Expected a failure for each input that panicked")
+	for i, failure := range failures {
+		assert.Equal(t, NewValues(2*i+1).String(), failure.Input.String(), "Expected the input that panicked")
+		assert.Equal(t, "odd input", failure.Panic, "Expected the panic value")
+		assert.NotEmpty(t, failure.Stack, "Expected the stack trace")
+	}
+
+	assert.PanicsWithValue(t, "odd input", func() {
+		fn.RunWithConfig(20, RunConfig{Workers: 1})
+	}, "Expected the panic to be raised again without RecoverPanics")
+}
+
+func TestFailures(t *testing.T) {
+	fn := NewFnWithConfig(func(i int) (int, error) {
+		if i < 0 {
+			return 0, errors.New("negative input")
+		}
+		return i, nil
+	}, 10, RunConfig{Workers: 1}, Sequence(-5, 5, 1))
+
+	set := fn.ValuesSet()
+	inputs, outputs := setFloats(t, set)
+	assert.Equal(t, inputs, outputs, "Expected the output without the error")
+	assert.Len(t, inputs, 5, "Expected the pairs of the inputs without an error")
+	failures := set.Failures()
+	require.Len(t, failures, 5, "Expected a failure for each input with an error")
+	for i, failure := range failures {
+		assert.Equal(t, NewValues(i-5).String(), failure.Input.String(), "Expected the input with an error")
+		assert.EqualError(t, failure.Err, "negative input", "Expected the error of the function")
+		assert.Nil(t, failure.Panic, "Expected no panic")
+	}
+
+	pl := Plot{Fn: fn, X: &StdAxix{}, Y: &StdAxix{}, Scalars: ScalarConfig{Input: Signed}, Failures: true}
+	p, err := pl.render()
+	require.NoError(t, err, "Error rendering plot")
+	assert.Equal(t, -5.0, p.X.Min, "Expected the X axis to include the failures")
+}
+
+func TestReplay(t *testing.T) {
+	before := NewFn(func(i int) int { return i * i }, 20, IntRange(0, 1000))
+	after := newFn(func(i int) int { return i * 2 }, NewValuesSet(NewMemoryStore(0)))
+	require.NoError(t, after.Replay(before.ValuesSet()), "Error replaying inputs")
+	beforeInputs, _ := setFloats(t, before.ValuesSet())
+	afterInputs, afterOutputs := setFloats(t, after.ValuesSet())
+	assert.Equal(t, beforeInputs, afterInputs, "Expected the same inputs in the same order")
+	for i := range afterInputs {
+		assert.Equal(t, afterInputs[i]*2, afterOutputs[i], "Expected the outputs of the replaying function")
+	}
+
+	strings := newFn(func(s string) int { return len(s) }, NewValuesSet(NewMemoryStore(0)))
+	assert.Error(t, strings.Replay(before.ValuesSet()), "Expected an error replaying inputs of another type")
+	assert.Error(t, FnOf(NewValuesSet(nil)).Replay(before.ValuesSet()), "Expected an error replaying without a function")
+}
+
+func TestReplayPanic(t *testing.T) {
+	panics := func(i int) int {
+		if i == 3 {
+			panic("three")
+		}
+		return i
+	}
+	recovered := newFn(func(i int) int { return i }, NewValuesSet(NewMemoryStore(0)), Sequence(0, 10, 1))
+	require.NoError(t, recovered.RunWithConfig(10, RunConfig{Workers: 1, RecoverPanics: true}), "Error running function")
+	after := newFn(panics, NewValuesSet(NewMemoryStore(0)))
+	require.NoError(t, after.Replay(recovered.ValuesSet()), "Error replaying inputs of a run that recovers panics")
+	assert.Equal(t, 9, after.ValuesSet().Len(), "Expected the pairs of the inputs that didn't panic")
+	failures := after.ValuesSet().Failures()
+	require.Len(t, failures, 1, "Expected a failure for the input that panicked")
+	assert.Equal(t, "three", failures[0].Panic, "Expected the panic of the function")
+
+	unrecovered := newFn(func(i int) int { return i }, NewValuesSet(NewMemoryStore(0)), Sequence(0, 10, 1))
+	require.NoError(t, unrecovered.RunWithConfig(10, RunConfig{Workers: 1}), "Error running function")
+	after = newFn(panics, NewValuesSet(NewMemoryStore(0)))
+	err := after.Replay(unrecovered.ValuesSet())
+	require.IsType(t, &Failure{}, err, "Expected the panic as a *Failure")
+	assert.Equal(t, "three", err.(*Failure).Panic, "Expected the panic of the function")
+	assert.Equal(t, 3, after.ValuesSet().Len(), "Expected the pairs sampled before the panic")
+}
+
+func TestRunWithConfigTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	fn := newFn(func(i int) int {
+		if i == 3 {
+			<-release
+		}
+		return i
+	}, NewValuesSet(NewMemoryStore(0)), Sequence(0, 10, 1))
+
+	err := fn.RunWithConfig(10, RunConfig{Workers: 1, Timeout: 50 * time.Millisecond})
+	require.NoError(t, err, "Error running function")
+	set := fn.ValuesSet()
+	assert.Equal(t, 9, set.Len(), "Expected the pairs of the inputs that didn't time out")
+	failures := set.Failures()
+	require.Len(t, failures, 1, "Expected a failure for the input that timed out")
+	assert.Equal(t, NewValues(3).String(), failures[0].Input.String(), "Expected the input that timed out")
+	assert.Equal(t, ErrTimeout, failures[0].Err, "Expected a timeout")
+
+	pl := Plot{Fn: fn, X: &StdAxix{}, Y: &StdAxix{}, Failures: true}
+	_, err = pl.render()
+	assert.NoError(t, err, "Error rendering plot")
+}
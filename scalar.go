@@ -0,0 +1,272 @@
+package fnplot
+
+import (
+	"hash/fnv"
+	"math"
+	"math/big"
+	"math/cmplx"
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// defaultPrecision is the precision of big.NewFloat, which the scalar values
+// have always been converted with.
+const defaultPrecision = 53
+
+var (
+	precision   uint = defaultPrecision
+	precisionMu sync.RWMutex
+)
+
+// SetPrecision sets the precision, in bits, of the scalar values that
+// Values.Scalar converts collections of values to, and of the calculations on
+// scalar values by the axes, stats, and derived sets. The default precision is
+// 53 bits, which rounds integers larger than 2^53, like 256-bit hashes, so set
+// a larger precision to keep their low-order bits. Values that are converted
+// exactly, like big.Int values, times, and Hash values, keep their own
+// precision. If prec is zero, the default precision is used.
+func SetPrecision(prec uint) {
+	if prec == 0 {
+		prec = defaultPrecision
+	}
+	precisionMu.Lock()
+	defer precisionMu.Unlock()
+	precision = prec
+}
+
+// Precision returns the precision, in bits, set with SetPrecision.
+func Precision() uint {
+	precisionMu.RLock()
+	defer precisionMu.RUnlock()
+	return precision
+}
+
+// newFloat returns a new zero *big.Float with the precision set with
+// SetPrecision.
+func newFloat() *big.Float {
+	return new(big.Float).SetPrec(Precision())
+}
+
+// A ScalarStrategy converts a Values to a scalar value. RawBytes is the
+// default strategy. Any function with the same signature can be used as a
+// custom strategy.
+type ScalarStrategy func(vs Values) (*big.Float, error)
+
+// RawBytes converts a Values with Values.Scalar, interpreting the binary
+// representation of collections of values as a big-endian integer.
+var RawBytes ScalarStrategy = Values.Scalar
+
+// A ScalarConfig selects the strategies that convert the input and output
+// values of a ValuesSet or Plot to scalar values, like Hash for the inputs and
+// RawBytes for the outputs. If a strategy is nil, RawBytes is used.
+type ScalarConfig struct {
+	Input  ScalarStrategy
+	Output ScalarStrategy
+}
+
+// isZero returns true if the config uses the default strategies.
+func (sc ScalarConfig) isZero() bool {
+	return sc.Input == nil && sc.Output == nil
+}
+
+// input converts the input values to a scalar value.
+func (sc ScalarConfig) input(vs Values) (*big.Float, error) {
+	if sc.Input == nil {
+		return vs.Scalar()
+	}
+	return sc.Input(vs)
+}
+
+// output converts the output values to a scalar value.
+func (sc ScalarConfig) output(vs Values) (*big.Float, error) {
+	if sc.Output == nil {
+		return vs.Scalar()
+	}
+	return sc.Output(vs)
+}
+
+// FirstElement converts only the first of the values with Values.Scalar, like
+// the first argument of a function with multiple arguments.
+func FirstElement(vs Values) (*big.Float, error) {
+	if len(vs) == 0 {
+		return vs.Scalar()
+	}
+	return vs[:1].Scalar()
+}
+
+// SelectValues returns a ScalarStrategy that converts only the values at the
+// given indices with Values.ScalarOf. For example, SelectValues(0) as the input
+// strategy plots the first argument of each sample on the X axis.
+func SelectValues(indices ...int) ScalarStrategy {
+	return func(vs Values) (*big.Float, error) {
+		return vs.ScalarOf(indices...)
+	}
+}
+
+// Lexical converts a Values to a scalar value in [0, 1) that preserves the
+// lexicographic order of the binary representation of the values, so strings
+// are ordered like they would be in a dictionary instead of by their length.
+// The binary representation is the same one Values.Scalar uses, but is
+// interpreted as the digits of a base-256 fraction instead of an integer.
+func Lexical(vs Values) (*big.Float, error) {
+	b, err := vs.binary()
+	if err != nil {
+		return nil, err
+	}
+	// Keep every bit of the binary representation so that values that only
+	// differ in their last bytes are still ordered.
+	prec := uint(len(b) * 8)
+	if p := Precision(); prec < p {
+		prec = p
+	}
+	f := new(big.Float).SetPrec(prec).SetInt(new(big.Int).SetBytes(b))
+	return f.SetMantExp(f, -len(b)*8), nil
+}
+
+// Hash converts a Values to the 64-bit FNV-1a hash of the binary
+// representation of the values. Use Hash for values whose magnitude is
+// meaningless, like composite keys, to spread them uniformly across the axis.
+func Hash(vs Values) (*big.Float, error) {
+	b, err := vs.binary()
+	if err != nil {
+		return nil, err
+	}
+	h := fnv.New64a()
+	h.Write(b)
+	return new(big.Float).SetUint64(h.Sum64()), nil
+}
+
+// ByLength converts a Values to the length of its values, for plotting the size
+// of the inputs of a function instead of their content. The values must be
+// slices, arrays, maps, strings, or pointers to them. The lengths of multiple
+// values are added together.
+func ByLength(vs Values) (*big.Float, error) {
+	var n int
+	for _, value := range vs {
+		value = indirect(value)
+		switch value.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map, reflect.String:
+			n += value.Len()
+		case reflect.Invalid:
+			// Nil values have no length.
+		default:
+			return nil, errors.New("value has no length: " + value.Type().String())
+		}
+	}
+	return big.NewFloat(float64(n)), nil
+}
+
+// Signed converts a Values like Values.Scalar, except that if the first value
+// is a negative integer, the binary representation of the values is
+// interpreted as a two's complement integer, so the scalar value is negative.
+// Values.Scalar interprets the binary representation as an unsigned integer,
+// so negative integers become very large scalar values instead.
+func Signed(vs Values) (*big.Float, error) {
+	if len(vs) == 0 || !isNegativeInt(vs[0]) {
+		return vs.Scalar()
+	}
+	b, err := vs.binary()
+	if err != nil {
+		return nil, err
+	}
+	x := new(big.Int).SetBytes(b)
+	x.Sub(x, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+	return newFloat().SetInt(x), nil
+}
+
+// isNegativeInt returns true if the value is a negative signed integer.
+func isNegativeInt(value reflect.Value) bool {
+	value = indirect(value)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int() < 0
+	}
+	return false
+}
+
+// A ComplexPart is a part of a complex number used as its scalar value.
+type ComplexPart int
+
+const (
+	// ComplexAbs is the absolute value (magnitude) of a complex number.
+	ComplexAbs ComplexPart = iota
+	// ComplexReal is the real part of a complex number.
+	ComplexReal
+	// ComplexImag is the imaginary part of a complex number.
+	ComplexImag
+	// ComplexPhase is the phase (argument) of a complex number, in the range
+	// [-Pi, Pi].
+	ComplexPhase
+)
+
+// ComplexScalar returns a ScalarStrategy that converts individual complex
+// numbers to the given part. Other values are converted with Values.Scalar,
+// which converts individual complex numbers to their absolute value.
+func ComplexScalar(part ComplexPart) ScalarStrategy {
+	return func(vs Values) (*big.Float, error) {
+		if len(vs) != 1 {
+			return vs.Scalar()
+		}
+		value := indirect(vs[0])
+		if value.Kind() != reflect.Complex64 && value.Kind() != reflect.Complex128 {
+			return vs.Scalar()
+		}
+		c := value.Complex()
+		var f float64
+		switch part {
+		case ComplexAbs:
+			f = cmplx.Abs(c)
+		case ComplexReal:
+			f = real(c)
+		case ComplexImag:
+			f = imag(c)
+		case ComplexPhase:
+			f = cmplx.Phase(c)
+		default:
+			return nil, errors.Errorf("unknown complex part %d", part)
+		}
+		if math.IsNaN(f) {
+			return nil, errors.Errorf("complex number %v has no scalar value", c)
+		}
+		return big.NewFloat(f), nil
+	}
+}
+
+// Rescalar returns a new set with the pairs of the set, with the input and
+// output scalar values converted again with the given strategies. If a
+// strategy is nil, the pairs keep their scalar values. Use FnOf to plot the
+// new set.
+//
+// Sets read with ReadGob or LoadJSON and sets stored in a DiskStore or
+// SQLStore keep their formatted values as strings, so the strategies convert
+// those strings.
+func (set *ValuesSet) Rescalar(input, output ScalarStrategy) (*ValuesSet, error) {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	samples, err := set.samplesLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	rescaled := &ValuesSet{}
+	for _, s := range samples {
+		if input != nil {
+			if s.InputScalar, err = input(s.Input); err != nil {
+				return nil, withKind(ErrScalarConversion, errors.WithMessage(err, "error converting input "+s.Input.String()))
+			}
+			s.InputErr = nil
+		}
+		if output != nil {
+			if s.OutputScalar, err = output(s.Output); err != nil {
+				return nil, withKind(ErrScalarConversion, errors.WithMessage(err, "error converting output "+s.Output.String()))
+			}
+			s.OutputErr = nil
+		}
+		if err := rescaled.insertSample(s); err != nil {
+			return nil, err
+		}
+	}
+	return rescaled, nil
+}
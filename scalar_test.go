@@ -0,0 +1,347 @@
+package fnplot
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gonum.org/v1/plot/plotter"
+)
+
+func TestLexical(t *testing.T) {
+	tests := []struct {
+		description string
+		values      Values
+		expected    *big.Float
+	}{
+		{
+			description: "empty string",
+			values:      NewValues(""),
+			expected:    big.NewFloat(0),
+		},
+		{
+			description: "single byte string",
+			values:      NewValues("\x80"),
+			expected:    big.NewFloat(0.5),
+		},
+		{
+			description: "two byte string",
+			values:      NewValues("\x80\x40"),
+			expected:    big.NewFloat(0.5 + 0.25/256),
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			s, err := Lexical(test.values)
+			require.NoError(t, err, "Error calculating scalar value")
+			assert.Equal(t, 0, test.expected.Cmp(s), "Expected %s, got %s", test.expected, s)
+		})
+	}
+
+	// Strings must be ordered lexicographically regardless of their length.
+	ordered := []string{"", "a", "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", "aaaaaaaaaaaaaaaaaaaaaaaaaaaaab", "ab", "b", "ba"}
+	var prev *big.Float
+	for _, str := range ordered {
+		s, err := Lexical(NewValues(str))
+		require.NoError(t, err, "Error calculating scalar value")
+		if prev != nil {
+			assert.Equal(t, -1, prev.Cmp(s), "Expected scalar of %q to be larger than the previous string", str)
+		}
+		prev = s
+	}
+}
+
+func TestHash(t *testing.T) {
+	// The 64-bit FNV-1a hash of "a".
+	s, err := Hash(NewValues("a"))
+	require.NoError(t, err, "Error calculating scalar value")
+	assert.Equal(t, new(big.Float).SetUint64(0xaf63dc4c8601ec8c), s, "Expected and actual values are different")
+
+	// Equal values have equal hashes.
+	type key struct {
+		A string
+		B int
+	}
+	s1, err := Hash(NewValues(key{A: "a", B: 1}))
+	require.NoError(t, err, "Error calculating scalar value")
+	s2, err := Hash(NewValues(key{A: "a", B: 1}))
+	require.NoError(t, err, "Error calculating scalar value")
+	assert.Equal(t, s1, s2, "Expected equal values to have equal hashes")
+}
+
+func TestByLength(t *testing.T) {
+	tests := []struct {
+		description string
+		values      Values
+		expected    *big.Float
+		expectedErr bool
+	}{
+		{
+			description: "string",
+			values:      NewValues("abc"),
+			expected:    big.NewFloat(3),
+		},
+		{
+			description: "slice",
+			values:      NewValues([]int{1, 2}),
+			expected:    big.NewFloat(2),
+		},
+		{
+			description: "map",
+			values:      NewValues(map[string]int{"a": 1}),
+			expected:    big.NewFloat(1),
+		},
+		{
+			description: "nil slice",
+			values:      NewValues([]int(nil)),
+			expected:    big.NewFloat(0),
+		},
+		{
+			description: "multiple values",
+			values:      NewValues("abc", [2]int{}),
+			expected:    big.NewFloat(5),
+		},
+		{
+			description: "value without a length",
+			values:      NewValues(1),
+			expectedErr: true,
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			s, err := ByLength(test.values)
+			if test.expectedErr {
+				assert.Error(t, err, "Expected an error calculating scalar value")
+				return
+			}
+			require.NoError(t, err, "Error calculating scalar value")
+			assert.Equal(t, test.expected, s, "Expected and actual values are different")
+		})
+	}
+}
+
+func TestSigned(t *testing.T) {
+	tests := []struct {
+		description string
+		values      Values
+		expected    *big.Float
+	}{
+		{
+			description: "positive int",
+			values:      NewValues(200),
+			expected:    big.NewFloat(200),
+		},
+		{
+			description: "negative int",
+			values:      NewValues(-1),
+			expected:    big.NewFloat(-1),
+		},
+		{
+			description: "negative int16",
+			values:      NewValues(-200),
+			expected:    big.NewFloat(-200),
+		},
+		{
+			description: "negative int with another value",
+			values:      NewValues(-1, byte(5)),
+			expected:    big.NewFloat(-251),
+		},
+		{
+			description: "positive int with another value",
+			values:      NewValues(1, byte(5)),
+			expected:    big.NewFloat(261),
+		},
+		{
+			description: "negative float",
+			values:      NewValues(-1.5),
+			expected:    big.NewFloat(-1.5),
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			s, err := Signed(test.values)
+			require.NoError(t, err, "Error calculating scalar value")
+			assert.Equal(t, 0, test.expected.Cmp(s), "Expected %s, got %s", test.expected, s)
+		})
+	}
+}
+
+func TestComplexScalar(t *testing.T) {
+	tests := []struct {
+		description string
+		part        ComplexPart
+		values      Values
+		expected    *big.Float
+		expectedErr bool
+	}{
+		{
+			description: "absolute value",
+			part:        ComplexAbs,
+			values:      NewValues(complex(3, -4)),
+			expected:    big.NewFloat(5),
+		},
+		{
+			description: "real part",
+			part:        ComplexReal,
+			values:      NewValues(complex(3, -4)),
+			expected:    big.NewFloat(3),
+		},
+		{
+			description: "imaginary part",
+			part:        ComplexImag,
+			values:      NewValues(complex64(complex(3, -4))),
+			expected:    big.NewFloat(-4),
+		},
+		{
+			description: "phase",
+			part:        ComplexPhase,
+			values:      NewValues(complex(0, 1)),
+			expected:    big.NewFloat(math.Pi / 2),
+		},
+		{
+			description: "not a complex number",
+			part:        ComplexReal,
+			values:      NewValues(2.5),
+			expected:    big.NewFloat(2.5),
+		},
+		{
+			description: "NaN real part",
+			part:        ComplexReal,
+			values:      NewValues(complex(math.NaN(), 1)),
+			expectedErr: true,
+		},
+		{
+			description: "NaN imaginary part",
+			part:        ComplexImag,
+			values:      NewValues(complex(1, math.NaN())),
+			expectedErr: true,
+		},
+		{
+			description: "NaN phase",
+			part:        ComplexPhase,
+			values:      NewValues(complex(math.NaN(), 0)),
+			expectedErr: true,
+		},
+		{
+			description: "NaN absolute value",
+			part:        ComplexAbs,
+			values:      NewValues(complex(math.NaN(), 0)),
+			expectedErr: true,
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			s, err := ComplexScalar(test.part)(test.values)
+			if test.expectedErr {
+				assert.Error(t, err, "Expected an error calculating scalar value")
+				return
+			}
+			require.NoError(t, err, "Error calculating scalar value")
+			assert.Equal(t, test.expected, s, "Expected and actual values are different")
+		})
+	}
+}
+
+func TestRescalar(t *testing.T) {
+	set := &ValuesSet{}
+	for _, str := range []string{"b", "aa"} {
+		require.NoError(t, set.insert(NewValues(str), NewValues(len(str))), "Error inserting values")
+	}
+
+	rescaled, err := set.Rescalar(Lexical, nil)
+	require.NoError(t, err, "Error rescaling set")
+	inputs, outputs := setFloats(t, rescaled)
+	assert.True(t, inputs[0] > inputs[1], "Expected \"b\" to be larger than \"aa\"")
+	assert.Equal(t, []float64{1, 2}, outputs, "Expected outputs to keep their scalar values")
+
+	// The original set keeps its scalar values.
+	inputs, _ = setFloats(t, set)
+	assert.True(t, inputs[0] < inputs[1], "Expected the original set to be unchanged")
+}
+
+func TestScalarConfig(t *testing.T) {
+	set := NewValuesSetWithConfig(nil, ScalarConfig{Input: FirstElement})
+	require.NoError(t, set.insert(NewValues(2, "ignored"), NewValues(4)), "Error inserting values")
+	inputs, outputs := setFloats(t, set)
+	assert.Equal(t, []float64{2}, inputs, "Expected the input to be converted with FirstElement")
+	assert.Equal(t, []float64{4}, outputs, "Expected the output to be converted with RawBytes")
+
+	pl := Plot{
+		Fn:      FnOf(set),
+		X:       &StdAxix{},
+		Y:       &StdAxix{},
+		Scalars: ScalarConfig{Output: ByLength},
+	}
+	bigInputs, bigOutputs, err := pl.seriesScalars(pl.series())
+	require.Error(t, err, "Expected an error converting an int with ByLength")
+
+	set = NewValuesSet(nil)
+	require.NoError(t, set.insert(NewValues("b"), NewValues("abc")), "Error inserting values")
+	pl.Fn = FnOf(set)
+	bigInputs, bigOutputs, err = pl.seriesScalars(pl.series())
+	require.NoError(t, err, "Error converting values")
+	assert.Equal(t, big.NewFloat('b'), bigInputs[0][0], "Expected the input to be converted with RawBytes")
+	assert.Equal(t, big.NewFloat(3), bigOutputs[0][0], "Expected the output to be converted with ByLength")
+}
+
+func TestScalarErrorCached(t *testing.T) {
+	var calls int
+	failing := func(vs Values) (*big.Float, error) {
+		calls++
+		return nil, errors.New("failing strategy")
+	}
+	set := NewValuesSetWithConfig(nil, ScalarConfig{Output: failing})
+	assert.Error(t, set.insert(NewValues(1), NewValues(2)), "Expected an error inserting values")
+
+	_, _, err := set.scalars()
+	require.Error(t, err, "Expected an error converting values")
+	assert.Contains(t, err.Error(), "failing strategy", "Expected the error of the strategy")
+	assert.Equal(t, 1, calls, "Expected the values to be converted only once")
+}
+
+func TestSetPrecision(t *testing.T) {
+	defer SetPrecision(0)
+
+	// A 256-bit value with its lowest bit set.
+	b := make([]byte, 32)
+	b[0], b[31] = 0x80, 1
+	expected := new(big.Int).SetBytes(b)
+
+	s, err := NewValues(b).Scalar()
+	require.NoError(t, err, "Error calculating scalar value")
+	actual, _ := s.Int(nil)
+	assert.NotEqual(t, expected, actual, "Expected the default precision to round the value")
+
+	SetPrecision(256)
+	assert.Equal(t, uint(256), Precision(), "Expected the set precision")
+	s, err = NewValues(b).Scalar()
+	require.NoError(t, err, "Error calculating scalar value")
+	actual, _ = s.Int(nil)
+	assert.Equal(t, expected, actual, "Expected the value to keep every bit")
+
+	stats, err := newTestSet(t, []float64{1, 2}, []float64{1, 2}).Stats()
+	require.NoError(t, err, "Error summarizing set")
+	assert.Equal(t, uint(256), stats.Output.Mean.Prec(), "Expected stats to use the set precision")
+
+	SetPrecision(0)
+	assert.Equal(t, uint(53), Precision(), "Expected the default precision")
+}
+
+func TestPlotInverse(t *testing.T) {
+	pl := Plot{
+		Fn:      FnOf(newTestSet(t, []float64{1, 2}, []float64{10, 20})),
+		X:       &StdAxix{},
+		Y:       &StdAxix{},
+		Inverse: true,
+	}
+	points, err := pl.seriesPoints(pl.series())
+	require.NoError(t, err, "Error converting values")
+	assert.Equal(t, plotter.XYs{{X: 10, Y: 1}, {X: 20, Y: 2}}, points[0], "Expected the outputs on the X axis")
+}
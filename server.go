@@ -0,0 +1,140 @@
+package fnplot
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// defaultServeBatch is the number of samples run between plot updates if Serve
+// isn't given a batch size.
+const defaultServeBatch = 100
+
+// A server samples the functions of a plot in batches and serves the plot over
+// HTTP as it evolves.
+type server struct {
+	plot    Plot
+	samples int
+	batch   int
+
+	// mu serializes rendering because rendering sets the values of the plot
+	// axes.
+	mu   sync.Mutex
+	done int
+	err  error
+}
+
+// run samples the functions of the plot in batches until each function has
+// been run the requested number of times.
+func (s *server) run() {
+	err := runBatches(s.plot.series(), s.samples, s.batch, func(done int) error {
+		s.mu.Lock()
+		s.done = done
+		s.mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		s.mu.Lock()
+		s.err = err
+		s.mu.Unlock()
+	}
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/":
+		s.mu.Lock()
+		status := fmt.Sprintf("%d / %d samples", s.done, s.samples)
+		if s.err != nil {
+			status = s.err.Error()
+		}
+		s.mu.Unlock()
+		var buf bytes.Buffer
+		err := serverTemplate.Execute(&buf, struct {
+			Title  string
+			Status string
+		}{
+			Title:  s.plot.Title,
+			Status: status,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		buf.WriteTo(w)
+	case "/plot.png":
+		var buf bytes.Buffer
+		s.mu.Lock()
+		err := s.plot.Write(&buf, "png")
+		s.mu.Unlock()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Cache-Control", "no-store")
+		buf.WriteTo(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// Serve samples the functions of the plot in batches and serves the plot over
+// HTTP at the given address (e.g. "localhost:8080") while the samples are
+// collected. The page in the browser refreshes the plot every second, so long
+// sampling runs can be monitored. Each function of the plot is run samples
+// times in batches of batch samples (100 if batch is 0), in addition to the
+// samples run when it was created. Serve blocks until the HTTP server stops.
+func Serve(addr string, pl Plot, samples, batch int) error {
+	if batch <= 0 {
+		batch = defaultServeBatch
+	}
+	s := &server{plot: pl, samples: samples, batch: batch}
+	go s.run()
+
+	srv := &http.Server{
+		Addr:        addr,
+		Handler:     s,
+		ReadTimeout: 10 * time.Second,
+	}
+	return errors.WithMessage(srv.ListenAndServe(), "error serving plot")
+}
+
+var serverTemplate = template.Must(template.New("server").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  body { margin: 0; font-family: sans-serif; }
+  img { display: block; width: 100vw; }
+  #status { padding: 0 12px; color: #666; font-size: 12px; }
+</style>
+</head>
+<body>
+<img id="plot" src="plot.png" alt="{{.Title}}">
+<div id="status">{{.Status}}</div>
+<script>
+(function() {
+  var img = document.getElementById("plot");
+  var status = document.getElementById("status");
+  setInterval(function() {
+    var next = new Image();
+    next.onload = function() { img.src = next.src; };
+    next.src = "plot.png?t=" + Date.now();
+    fetch("./").then(function(r) { return r.text(); }).then(function(html) {
+      var doc = new DOMParser().parseFromString(html, "text/html");
+      status.textContent = doc.getElementById("status").textContent;
+    });
+  }, 1000);
+})();
+</script>
+</body>
+</html>
+`))
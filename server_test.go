@@ -0,0 +1,95 @@
+package fnplot
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerServeHTTP(t *testing.T) {
+	fn := NewFn(math.Sqrt, 10, Float64Range(0, 100))
+	s := &server{
+		plot:    Plot{Title: "math.Sqrt", Fn: fn, X: &StdAxix{}, Y: &StdAxix{}},
+		samples: 20,
+		batch:   10,
+	}
+	s.run()
+
+	tests := []struct {
+		description string
+		server      *server
+		path        string
+		status      int
+		contentType string
+		body        string
+	}{
+		{
+			description: "page",
+			server:      s,
+			path:        "/",
+			status:      http.StatusOK,
+			contentType: "text/html; charset=utf-8",
+			body:        "20 / 20 samples",
+		},
+		{
+			description: "plot",
+			server:      s,
+			path:        "/plot.png",
+			status:      http.StatusOK,
+			contentType: "image/png",
+			body:        "\x89PNG",
+		},
+		{
+			description: "not found",
+			server:      s,
+			path:        "/missing",
+			status:      http.StatusNotFound,
+		},
+		{
+			description: "sampling error",
+			server:      &server{plot: Plot{Fn: FnOf(NewValuesSet(nil))}, err: errors.New("error running series Fn")},
+			path:        "/",
+			status:      http.StatusOK,
+			body:        "error running series Fn",
+		},
+		{
+			description: "plot error",
+			server:      &server{plot: Plot{Fn: FnOf(NewValuesSet(nil)), X: &StdAxix{}, Y: &StdAxix{}}},
+			path:        "/plot.png",
+			status:      http.StatusInternalServerError,
+			body:        "no samples",
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			test.server.ServeHTTP(rec, httptest.NewRequest("GET", test.path, nil))
+			assert.Equal(t, test.status, rec.Code, "Expected the status code")
+			if test.contentType != "" {
+				assert.Equal(t, test.contentType, rec.Header().Get("Content-Type"), "Expected the content type")
+			}
+			assert.True(t, strings.Contains(rec.Body.String(), test.body), "Expected the body to contain %q", test.body)
+		})
+	}
+}
+
+func TestServerRunError(t *testing.T) {
+	s := &server{plot: Plot{Fn: FnOf(NewValuesSet(nil))}, samples: 10, batch: 5}
+	s.run()
+	require.Error(t, s.err, "Expected an error sampling a Fn without a function")
+	assert.Contains(t, s.err.Error(), "series Fn", "Expected the name of the failing series")
+}
+
+func TestServeInvalidAddress(t *testing.T) {
+	pl := Plot{Fn: NewFn(math.Sqrt, 10, Float64Range(0, 100)), X: &StdAxix{}, Y: &StdAxix{}}
+	err := Serve("invalid address", pl, 0, 0)
+	require.Error(t, err, "Expected an error serving at an invalid address")
+	assert.Contains(t, err.Error(), "error serving plot", "Expected the error message")
+}
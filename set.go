@@ -0,0 +1,204 @@
+package fnplot
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Len returns the number of input/output pairs in the set.
+func (set *ValuesSet) Len() int {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	if set.store == nil {
+		return 0
+	}
+	return set.store.Len()
+}
+
+// Each calls fn with the input and output values, and their scalar values, of
+// each pair in the set, in the order they're stored, until fn returns false.
+// The scalar values are nil if the values couldn't be converted to a scalar.
+// If the set keeps its pairs in a MemoryStore, Each iterates over a copy of
+// the pairs, so fn can use the set and the set can be sampled concurrently.
+// Otherwise, Each iterates over the store directly, and fn must not sample
+// more pairs into the set.
+func (set *ValuesSet) Each(fn func(input, output Values, inScalar, outScalar *big.Float) bool) error {
+	set.mu.RLock()
+	store, ok := set.store.(*MemoryStore)
+	if !ok {
+		defer set.mu.RUnlock()
+		if set.store == nil {
+			return nil
+		}
+		err := set.store.Iterate(func(s Sample) bool {
+			return fn(s.Input, s.Output, s.InputScalar, s.OutputScalar)
+		})
+		return errors.WithMessage(err, "error reading samples")
+	}
+	samples := make([]Sample, len(store.samples))
+	copy(samples, store.samples)
+	set.mu.RUnlock()
+
+	for _, s := range samples {
+		if !fn(s.Input, s.Output, s.InputScalar, s.OutputScalar) {
+			break
+		}
+	}
+	return nil
+}
+
+// OnSample registers fn to be called with each sample inserted into the set
+// from then on, after it's stored, like for streaming samples to a live
+// dashboard while the function is sampled. The samples of concurrent workers
+// are passed to fn concurrently, and fn can use the set.
+func (set *ValuesSet) OnSample(fn func(s Sample)) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.onSample = append(set.onSample, fn)
+}
+
+// filter returns a new set with the pairs of the set that keep returns true
+// for. The pairs keep their converted scalar values.
+func (set *ValuesSet) filter(keep func(s Sample) bool) (*ValuesSet, error) {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	samples, err := set.samplesLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := &ValuesSet{}
+	for _, s := range samples {
+		if keep(s) {
+			if err := filtered.insertSample(s); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return filtered, nil
+}
+
+// Filter returns a new set with only the pairs of the set whose input and
+// output values satisfy pred. Use FnOf to plot the filtered set.
+func (set *ValuesSet) Filter(pred func(input, output Values) bool) (*ValuesSet, error) {
+	return set.filter(func(s Sample) bool {
+		return pred(s.Input, s.Output)
+	})
+}
+
+// Between returns a new set with only the pairs of the set whose input scalar
+// values are between minInput and maxInput, inclusive. A nil bound doesn't
+// limit the inputs. Use FnOf to plot a zoomed in region of the set.
+func (set *ValuesSet) Between(minInput, maxInput *big.Float) (*ValuesSet, error) {
+	return set.filter(func(s Sample) bool {
+		in := s.InputScalar
+		if in == nil {
+			return false
+		}
+		return (minInput == nil || in.Cmp(minInput) >= 0) &&
+			(maxInput == nil || in.Cmp(maxInput) <= 0)
+	})
+}
+
+// A DedupeMode determines which output is kept for an input that was sampled
+// more than once.
+type DedupeMode int
+
+const (
+	// KeepFirst keeps the first pair sampled with each input.
+	KeepFirst DedupeMode = iota
+	// MeanOutput keeps a single pair with the mean output of the pairs with
+	// each input.
+	MeanOutput
+	// MedianOutput keeps a single pair with the median output of the pairs
+	// with each input.
+	MedianOutput
+	// MinOutput keeps a single pair with the smallest output of the pairs with
+	// each input.
+	MinOutput
+	// MaxOutput keeps a single pair with the largest output of the pairs with
+	// each input.
+	MaxOutput
+)
+
+// Dedupe returns a new set with a single pair for each distinct input scalar
+// value, in the order that the inputs were first sampled. Generators often
+// produce the same input many times, which adds plot points without adding
+// information. The mode determines the output of inputs with more than one
+// pair.
+func (set *ValuesSet) Dedupe(mode DedupeMode) (*ValuesSet, error) {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	samples, err := set.samplesLocked()
+	if err != nil {
+		return nil, err
+	}
+	inputs, outputs, err := sampleScalars(samples)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group the indexes of the pairs by input, in the order they're first seen.
+	var order []string
+	groups := make(map[string][]int)
+	for i := range inputs {
+		key := inputs[i].Text('g', -1)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	deduped := &ValuesSet{}
+	for _, key := range order {
+		group := groups[key]
+		s := samples[group[0]]
+		if mode != KeepFirst && len(group) > 1 {
+			groupOutputs := make([]*big.Float, len(group))
+			for i, j := range group {
+				groupOutputs[i] = outputs[j]
+			}
+			out, err := aggregate(mode, groupOutputs)
+			if err != nil {
+				return nil, err
+			}
+			f, _ := out.Float64()
+			s.Output = NewValues(f)
+			s.OutputScalar = out
+		}
+		if err := deduped.insertSample(s); err != nil {
+			return nil, err
+		}
+	}
+	return deduped, nil
+}
+
+// aggregate combines the values into a single value as determined by the mode.
+func aggregate(mode DedupeMode, values []*big.Float) (*big.Float, error) {
+	sorted := make([]*big.Float, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) == -1 })
+
+	switch mode {
+	case MeanOutput:
+		sum := newFloat()
+		for _, v := range values {
+			sum.Add(sum, v)
+		}
+		return sum.Quo(sum, big.NewFloat(float64(len(values)))), nil
+	case MedianOutput:
+		mid := len(sorted) / 2
+		if len(sorted)%2 == 1 {
+			return sorted[mid], nil
+		}
+		median := newFloat().Add(sorted[mid-1], sorted[mid])
+		return median.Quo(median, big.NewFloat(2)), nil
+	case MinOutput:
+		return sorted[0], nil
+	case MaxOutput:
+		return sorted[len(sorted)-1], nil
+	}
+	return nil, errors.Errorf("unknown dedupe mode %d", mode)
+}
@@ -0,0 +1,152 @@
+package fnplot
+
+import (
+	"math/big"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSet returns a set with a pair for each input and output.
+func newTestSet(t *testing.T, inputs, outputs []float64) *ValuesSet {
+	set := &ValuesSet{}
+	for i := range inputs {
+		require.NoError(t, set.insert(NewValues(inputs[i]), NewValues(outputs[i])), "Error inserting values")
+	}
+	return set
+}
+
+// setFloats returns the input and output scalar values of the set as float64.
+func setFloats(t *testing.T, set *ValuesSet) (inputs, outputs []float64) {
+	bigInputs, bigOutputs, err := set.scalars()
+	require.NoError(t, err, "Error converting values")
+	inputs = make([]float64, len(bigInputs))
+	outputs = make([]float64, len(bigOutputs))
+	for i := range bigInputs {
+		inputs[i], _ = bigInputs[i].Float64()
+		outputs[i], _ = bigOutputs[i].Float64()
+	}
+	return inputs, outputs
+}
+
+func TestFilter(t *testing.T) {
+	set := newTestSet(t, []float64{0, 1, 2, 3}, []float64{10, 11, 12, 13})
+	filtered, err := set.Filter(func(input, output Values) bool {
+		return input[0].Float() != 0 && output[0].Float() < 13
+	})
+	require.NoError(t, err, "Error filtering set")
+
+	inputs, outputs := setFloats(t, filtered)
+	assert.Equal(t, []float64{1, 2}, inputs, "Expected and actual inputs are different")
+	assert.Equal(t, []float64{11, 12}, outputs, "Expected and actual outputs are different")
+	assert.Equal(t, 4, set.Len(), "Expected the original set to be unchanged")
+}
+
+func TestDedupe(t *testing.T) {
+	set := newTestSet(t, []float64{2, 1, 2, 2, 1}, []float64{4, 1, 8, 3, 5})
+	tests := []struct {
+		mode            DedupeMode
+		expectedOutputs []float64
+	}{
+		{mode: KeepFirst, expectedOutputs: []float64{4, 1}},
+		{mode: MeanOutput, expectedOutputs: []float64{5, 3}},
+		{mode: MedianOutput, expectedOutputs: []float64{4, 3}},
+		{mode: MinOutput, expectedOutputs: []float64{3, 1}},
+		{mode: MaxOutput, expectedOutputs: []float64{8, 5}},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(strconv.Itoa(int(test.mode)), func(t *testing.T) {
+			deduped, err := set.Dedupe(test.mode)
+			require.NoError(t, err, "Error deduping set")
+			inputs, outputs := setFloats(t, deduped)
+			assert.Equal(t, []float64{2, 1}, inputs, "Expected and actual inputs are different")
+			assert.Equal(t, test.expectedOutputs, outputs, "Expected and actual outputs are different")
+		})
+	}
+
+	_, err := set.Dedupe(DedupeMode(-1))
+	assert.Error(t, err, "Expected an error for an unknown dedupe mode")
+}
+
+func TestReservoir(t *testing.T) {
+	store := NewMemoryStore(10)
+	store.rng = rand.New(rand.NewSource(1))
+	set := NewValuesSet(store)
+	for i := 0; i < 1000; i++ {
+		require.NoError(t, set.insert(NewValues(float64(i)), NewValues(0.0)), "Error inserting values")
+	}
+	assert.Equal(t, 10, set.Len(), "Expected and actual number of kept pairs are different")
+	assert.Equal(t, 1000, set.Sampled(), "Expected and actual number of sampled pairs are different")
+
+	inputs, _ := setFloats(t, set)
+	late := 0
+	for _, input := range inputs {
+		if input >= 100 {
+			late++
+		}
+	}
+	assert.True(t, late > 0, "Expected some kept pairs to be sampled after the set was full")
+}
+
+func TestNewFnWithCapacity(t *testing.T) {
+	fn := NewFnWithCapacity(func(x float64) float64 { return x }, 500, 50, Float64Range(0, 1))
+	assert.Equal(t, 50, fn.ValuesSet().Len(), "Expected and actual number of kept pairs are different")
+	assert.Equal(t, 500, fn.ValuesSet().Sampled(), "Expected and actual number of sampled pairs are different")
+}
+
+func TestEach(t *testing.T) {
+	set := newTestSet(t, []float64{1, 2, 3}, []float64{10, 20, 30})
+	assert.Equal(t, 3, set.Len(), "Expected and actual lengths are different")
+
+	var inputs, outScalars []float64
+	err := set.Each(func(input, output Values, inScalar, outScalar *big.Float) bool {
+		inputs = append(inputs, input[0].Float())
+		f, _ := outScalar.Float64()
+		outScalars = append(outScalars, f)
+		return len(inputs) < 2
+	})
+	require.NoError(t, err, "Error iterating over set")
+	assert.Equal(t, []float64{1, 2}, inputs, "Expected and actual inputs are different")
+	assert.Equal(t, []float64{10, 20}, outScalars, "Expected and actual output scalars are different")
+}
+
+func TestBetween(t *testing.T) {
+	set := newTestSet(t, []float64{3, 1, 4, 1, 5, 9, 2, 6}, []float64{0, 1, 2, 3, 4, 5, 6, 7})
+	tests := []struct {
+		description    string
+		min, max       *big.Float
+		expectedInputs []float64
+	}{
+		{description: "bounded", min: big.NewFloat(2), max: big.NewFloat(5), expectedInputs: []float64{3, 4, 5, 2}},
+		{description: "no minimum", max: big.NewFloat(2), expectedInputs: []float64{1, 1, 2}},
+		{description: "no maximum", min: big.NewFloat(6), expectedInputs: []float64{9, 6}},
+		{description: "empty", min: big.NewFloat(7), max: big.NewFloat(8), expectedInputs: []float64{}},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			between, err := set.Between(test.min, test.max)
+			require.NoError(t, err, "Error selecting input range")
+			inputs, _ := setFloats(t, between)
+			assert.Equal(t, test.expectedInputs, inputs, "Expected and actual inputs are different")
+		})
+	}
+}
+
+func TestOnSample(t *testing.T) {
+	set := newTestSet(t, []float64{1}, []float64{10})
+	var inputs []float64
+	var lens []int
+	set.OnSample(func(s Sample) {
+		inputs = append(inputs, s.Input[0].Float())
+		lens = append(lens, set.Len())
+	})
+	require.NoError(t, set.insert(NewValues(2.0), NewValues(20.0)), "Error inserting values")
+	require.NoError(t, set.insert(NewValues(3.0), NewValues(30.0)), "Error inserting values")
+	assert.Equal(t, []float64{2, 3}, inputs, "Expected a call for each sample inserted after registering")
+	assert.Equal(t, []int{2, 3}, lens, "Expected each sample to be stored before the call")
+}
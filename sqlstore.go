@@ -0,0 +1,156 @@
+package fnplot
+
+import (
+	"database/sql"
+	"encoding/json"
+	"math/big"
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+// validTable matches the table names that can be used in SQL statements
+// without quoting.
+var validTable = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQLStore is a SampleStore that keeps the samples in a SQL database table, so
+// they survive crashes, can be queried with SQL, and can be plotted again by
+// later runs. The SQL is written for SQLite, so open the database with a
+// SQLite driver like github.com/mattn/go-sqlite3 or modernc.org/sqlite.
+//
+// Each row of the table has the input and output scalar values as REAL
+// columns for querying ("input" and "output"), the exact scalar values as
+// TEXT columns ("input_scalar" and "output_scalar"), and the formatted input
+// and output values ("input_values" and "output_values"). The labels of each
+// sample are a JSON object, or NULL ("labels"). Like the sets read with
+// ReadGob, the samples read from a SQLStore keep their formatted values as
+// strings.
+type SQLStore struct {
+	db     *sql.DB
+	table  string
+	insert *sql.Stmt
+	mm     minMax
+	n      int
+}
+
+// NewSQLStore creates a SampleStore that keeps the samples in the given table
+// of the database, creating the table if it doesn't exist. Samples already in
+// the table are kept, so a store of an existing table can be plotted with
+// FnOf(NewValuesSet(store)). Close the store when it's no longer used.
+func NewSQLStore(db *sql.DB, table string) (*SQLStore, error) {
+	if !validTable.MatchString(table) {
+		return nil, errors.Errorf("invalid table name %q", table)
+	}
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS ` + table + ` (
+		id INTEGER PRIMARY KEY,
+		input REAL,
+		output REAL,
+		input_scalar TEXT,
+		output_scalar TEXT,
+		input_values TEXT NOT NULL,
+		output_values TEXT NOT NULL,
+		labels TEXT
+	)`)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error creating sample table")
+	}
+	insert, err := db.Prepare(`INSERT INTO ` + table + ` (
+		input, output, input_scalar, output_scalar, input_values, output_values, labels
+	) VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error preparing sample insert")
+	}
+
+	ss := &SQLStore{db: db, table: table, insert: insert}
+	err = ss.Iterate(func(s Sample) bool {
+		ss.mm.update(s)
+		ss.n++
+		return true
+	})
+	if err != nil {
+		insert.Close()
+		return nil, err
+	}
+	return ss, nil
+}
+
+func (ss *SQLStore) Insert(s Sample) error {
+	input, inputScalar := sqlScalar(s.InputScalar)
+	output, outputScalar := sqlScalar(s.OutputScalar)
+	var labels sql.NullString
+	if s.Labels != nil {
+		encoded, err := json.Marshal(s.Labels)
+		if err != nil {
+			return errors.WithMessage(err, "error encoding sample labels")
+		}
+		labels = sql.NullString{String: string(encoded), Valid: true}
+	}
+	_, err := ss.insert.Exec(input, output, inputScalar, outputScalar, s.Input.String(), s.Output.String(), labels)
+	if err != nil {
+		return errors.WithMessage(err, "error inserting sample")
+	}
+	ss.mm.update(s)
+	ss.n++
+	return nil
+}
+
+// sqlScalar returns the scalar value as a float64 and as exact text, or as
+// NULLs if it's nil.
+func sqlScalar(f *big.Float) (sql.NullFloat64, sql.NullString) {
+	if f == nil {
+		return sql.NullFloat64{}, sql.NullString{}
+	}
+	approx, _ := f.Float64()
+	return sql.NullFloat64{Float64: approx, Valid: true}, sql.NullString{String: f.Text('g', -1), Valid: true}
+}
+
+func (ss *SQLStore) Iterate(fn func(s Sample) bool) error {
+	rows, err := ss.db.Query(`SELECT input_scalar, output_scalar, input_values, output_values, labels FROM ` + ss.table + ` ORDER BY id`)
+	if err != nil {
+		return errors.WithMessage(err, "error querying samples")
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var inputScalar, outputScalar, labels sql.NullString
+		var inputValues, outputValues string
+		if err := rows.Scan(&inputScalar, &outputScalar, &inputValues, &outputValues, &labels); err != nil {
+			return errors.WithMessage(err, "error reading sample")
+		}
+		s := Sample{
+			Input:  NewValues(inputValues),
+			Output: NewValues(outputValues),
+		}
+		if inputScalar.Valid {
+			if s.InputScalar, err = parseScalar(inputScalar.String); err != nil {
+				return errors.WithMessage(err, "error parsing input scalar")
+			}
+		}
+		if outputScalar.Valid {
+			if s.OutputScalar, err = parseScalar(outputScalar.String); err != nil {
+				return errors.WithMessage(err, "error parsing output scalar")
+			}
+		}
+		if labels.Valid {
+			if err := json.Unmarshal([]byte(labels.String), &s.Labels); err != nil {
+				return errors.WithMessage(err, "error decoding sample labels")
+			}
+		}
+		if !fn(s) {
+			return nil
+		}
+	}
+	return errors.WithMessage(rows.Err(), "error reading samples")
+}
+
+func (ss *SQLStore) MinMax() (minInput, maxInput, minOutput, maxOutput *big.Float) {
+	return ss.mm.minInput, ss.mm.maxInput, ss.mm.minOutput, ss.mm.maxOutput
+}
+
+func (ss *SQLStore) Len() int {
+	return ss.n
+}
+
+// Close releases the resources of the store. It doesn't close the database.
+func (ss *SQLStore) Close() error {
+	return errors.WithMessage(ss.insert.Close(), "error closing sample insert")
+}
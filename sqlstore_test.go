@@ -0,0 +1,183 @@
+package fnplot
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDriver is a database/sql driver that understands just the statements of
+// SQLStore, keeping the rows of each database name in memory.
+type fakeDriver struct {
+	mu  sync.Mutex
+	dbs map[string]*[][]driver.Value
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	rows, ok := d.dbs[name]
+	if !ok {
+		rows = &[][]driver.Value{}
+		d.dbs[name] = rows
+	}
+	return &fakeConn{driver: d, rows: rows}, nil
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+	rows   *[][]driver.Value
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: strings.TrimSpace(query)}, nil
+}
+
+func (*fakeConn) Close() error { return nil }
+
+func (*fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions aren't supported")
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (*fakeStmt) Close() error { return nil }
+
+func (*fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE"):
+	case strings.HasPrefix(s.query, "INSERT"):
+		s.conn.driver.mu.Lock()
+		*s.conn.rows = append(*s.conn.rows, append([]driver.Value(nil), args...))
+		s.conn.driver.mu.Unlock()
+	default:
+		return nil, errors.New("unsupported statement: " + s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if !strings.HasPrefix(s.query, "SELECT") {
+		return nil, errors.New("unsupported query: " + s.query)
+	}
+	s.conn.driver.mu.Lock()
+	defer s.conn.driver.mu.Unlock()
+	// The inserted rows are input, output, input_scalar, output_scalar,
+	// input_values, output_values, and labels, and the query selects all but
+	// the first two.
+	rows := make([][]driver.Value, len(*s.conn.rows))
+	for i, row := range *s.conn.rows {
+		rows[i] = row[2:]
+	}
+	return &fakeRows{rows: rows}, nil
+}
+
+type fakeRows struct {
+	rows [][]driver.Value
+}
+
+func (*fakeRows) Columns() []string {
+	return []string{"input_scalar", "output_scalar", "input_values", "output_values", "labels"}
+}
+
+func (*fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+var registerFakeDriver sync.Once
+
+// openFakeDB opens a new empty database of the fake driver.
+func openFakeDB(t *testing.T) *sql.DB {
+	registerFakeDriver.Do(func() {
+		sql.Register("fnplotfake", &fakeDriver{dbs: make(map[string]*[][]driver.Value)})
+	})
+	db, err := sql.Open("fnplotfake", t.Name())
+	require.NoError(t, err, "Error opening fake database")
+	return db
+}
+
+func TestNewSQLStoreInvalidTable(t *testing.T) {
+	for _, table := range []string{"", "1samples", "samples; DROP TABLE samples", `"samples"`} {
+		table := table // Capture range variable.
+		t.Run(table, func(t *testing.T) {
+			_, err := NewSQLStore(nil, table)
+			assert.Error(t, err, "Expected an error for an invalid table name")
+		})
+	}
+}
+
+func TestSQLScalar(t *testing.T) {
+	approx, exact := sqlScalar(big.NewFloat(1.25))
+	assert.Equal(t, sql.NullFloat64{Float64: 1.25, Valid: true}, approx, "Expected and actual REAL values are different")
+	assert.Equal(t, sql.NullString{String: "1.25", Valid: true}, exact, "Expected and actual TEXT values are different")
+
+	approx, exact = sqlScalar(nil)
+	assert.False(t, approx.Valid, "Expected a NULL REAL value")
+	assert.False(t, exact.Valid, "Expected a NULL TEXT value")
+}
+
+func TestSQLStore(t *testing.T) {
+	db := openFakeDB(t)
+	defer db.Close()
+	store, err := NewSQLStore(db, "samples")
+	require.NoError(t, err, "Error creating SQL store")
+
+	set := NewValuesSet(store)
+	require.NoError(t, set.insertLabeled(NewValues(1.5), NewValues(4), Labels{"case": "small"}), "Error inserting values")
+	require.NoError(t, set.insert(NewValues(3), NewValues(-2.25)), "Error inserting values")
+	require.NoError(t, store.Close(), "Error closing SQL store")
+	assert.Equal(t, 2, store.Len(), "Expected and actual number of samples are different")
+	minInput, maxInput, minOutput, maxOutput := store.MinMax()
+	assert.Zero(t, big.NewFloat(1.5).Cmp(minInput), "Expected and actual smallest inputs are different")
+	assert.Zero(t, big.NewFloat(3).Cmp(maxInput), "Expected and actual largest inputs are different")
+	assert.Zero(t, big.NewFloat(-2.25).Cmp(minOutput), "Expected and actual smallest outputs are different")
+	assert.Zero(t, big.NewFloat(4).Cmp(maxOutput), "Expected and actual largest outputs are different")
+
+	// A new store of the same table reads the samples already in it.
+	reopened, err := NewSQLStore(db, "samples")
+	require.NoError(t, err, "Error reopening SQL store")
+	defer reopened.Close()
+	assert.Equal(t, 2, reopened.Len(), "Expected the samples already in the table")
+	_, maxInput, _, _ = reopened.MinMax()
+	assert.Zero(t, big.NewFloat(3).Cmp(maxInput), "Expected the largest input of the samples already in the table")
+
+	var samples []Sample
+	require.NoError(t, reopened.Iterate(func(s Sample) bool {
+		samples = append(samples, s)
+		return true
+	}), "Error iterating samples")
+	require.Len(t, samples, 2, "Expected and actual number of samples read are different")
+	assert.Zero(t, big.NewFloat(1.5).Cmp(samples[0].InputScalar), "Expected the input scalar value")
+	assert.Zero(t, big.NewFloat(4).Cmp(samples[0].OutputScalar), "Expected the output scalar value")
+	assert.Equal(t, "1.5", samples[0].Input.String(), "Expected the formatted input values")
+	assert.Equal(t, Labels{"case": "small"}, samples[0].Labels, "Expected the labels")
+	assert.Equal(t, "-2.25", samples[1].Output.String(), "Expected the formatted output values")
+	assert.Nil(t, samples[1].Labels, "Expected no labels")
+
+	var n int
+	require.NoError(t, reopened.Iterate(func(Sample) bool {
+		n++
+		return false
+	}), "Error iterating samples")
+	assert.Equal(t, 1, n, "Expected iterating to stop when fn returns false")
+}
@@ -0,0 +1,108 @@
+package fnplot
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// A Summary describes the distribution of a set of scalar values. The values
+// are nil if there are no values.
+type Summary struct {
+	Count  int
+	Min    *big.Float
+	Max    *big.Float
+	Mean   *big.Float
+	Median *big.Float
+	// StdDev is the population standard deviation of the values.
+	StdDev *big.Float
+	// Percentiles are the requested percentiles (between 0 and 100) of the
+	// values, found with the nearest-rank method.
+	Percentiles map[float64]*big.Float
+}
+
+// String formats the count, mean, median, and standard deviation of the
+// values.
+func (s Summary) String() string {
+	if s.Count == 0 {
+		return "n=0"
+	}
+	return fmt.Sprintf("n=%d mean=%s median=%s stddev=%s",
+		s.Count, s.Mean.Text('g', 4), s.Median.Text('g', 4), s.StdDev.Text('g', 4))
+}
+
+// Stats summarizes the input and output scalar values of a ValuesSet.
+type Stats struct {
+	Input  Summary
+	Output Summary
+}
+
+// Stats returns a summary of the input and output scalar values of the set,
+// including the given percentiles (between 0 and 100).
+func (set *ValuesSet) Stats(percentiles ...float64) (Stats, error) {
+	inputs, outputs, err := set.scalars()
+	if err != nil {
+		return Stats{}, err
+	}
+	return Stats{
+		Input:  summarize(inputs, percentiles),
+		Output: summarize(outputs, percentiles),
+	}, nil
+}
+
+// summarize returns a summary of the values, including the given percentiles.
+func summarize(values []*big.Float, percentiles []float64) Summary {
+	s := Summary{
+		Count:       len(values),
+		Percentiles: make(map[float64]*big.Float, len(percentiles)),
+	}
+	if len(values) == 0 {
+		return s
+	}
+	sorted := sortedFloats(values)
+	s.Min = sorted[0]
+	s.Max = sorted[len(sorted)-1]
+
+	n := big.NewFloat(float64(len(values)))
+	s.Mean = newFloat()
+	for _, v := range values {
+		s.Mean.Add(s.Mean, v)
+	}
+	s.Mean.Quo(s.Mean, n)
+
+	variance := newFloat()
+	for _, v := range values {
+		d := newFloat().Sub(v, s.Mean)
+		variance.Add(variance, d.Mul(d, d))
+	}
+	s.StdDev = newFloat().Sqrt(variance.Quo(variance, n))
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		s.Median = sorted[mid]
+	} else {
+		s.Median = newFloat().Add(sorted[mid-1], sorted[mid])
+		s.Median.Quo(s.Median, big.NewFloat(2))
+	}
+
+	for _, p := range percentiles {
+		s.Percentiles[p] = nearestRank(sorted, p)
+	}
+	return s
+}
+
+// statsCaption returns a line with a summary of the outputs of each series.
+func (pl Plot) statsCaption() (string, error) {
+	series := pl.series()
+	lines := make([]string, len(series))
+	for i := range series {
+		stats, err := series[i].Fn.ValuesSet().Stats()
+		if err != nil {
+			return "", errors.WithMessage(err, "error summarizing series "+series[i].Name)
+		}
+		lines[i] = series[i].Name + ": " + stats.Output.String()
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,47 @@
+package fnplot
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	set := newTestSet(t, []float64{1, 2, 3, 4}, []float64{2, 4, 4, 6})
+	stats, err := set.Stats(25, 100)
+	require.NoError(t, err, "Error summarizing set")
+
+	tests := []struct {
+		description string
+		actual      *big.Float
+		expected    float64
+	}{
+		{description: "input min", actual: stats.Input.Min, expected: 1},
+		{description: "input max", actual: stats.Input.Max, expected: 4},
+		{description: "input mean", actual: stats.Input.Mean, expected: 2.5},
+		{description: "input median", actual: stats.Input.Median, expected: 2.5},
+		{description: "output mean", actual: stats.Output.Mean, expected: 4},
+		{description: "output median", actual: stats.Output.Median, expected: 4},
+		{description: "output stddev", actual: stats.Output.StdDev, expected: 1.4142135623730951},
+		{description: "output p25", actual: stats.Output.Percentiles[25], expected: 2},
+		{description: "output p100", actual: stats.Output.Percentiles[100], expected: 6},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			actual, _ := test.actual.Float64()
+			assert.InDelta(t, test.expected, actual, 1e-12, "Expected and actual values are different")
+		})
+	}
+	assert.Equal(t, 4, stats.Output.Count, "Expected and actual counts are different")
+	assert.Equal(t, "n=4 mean=4 median=4 stddev=1.414", stats.Output.String(), "Expected and actual summaries are different")
+}
+
+func TestStatsEmpty(t *testing.T) {
+	stats, err := (&ValuesSet{}).Stats(50)
+	require.NoError(t, err, "Error summarizing set")
+	assert.Nil(t, stats.Output.Mean, "Expected no mean")
+	assert.Equal(t, "n=0", stats.Output.String(), "Expected and actual summaries are different")
+}
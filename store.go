@@ -0,0 +1,127 @@
+package fnplot
+
+import (
+	"math/big"
+	"math/rand"
+	"time"
+)
+
+// A Sample is an input/output pair of a sampled function along with the scalar
+// values of the input and output.
+type Sample struct {
+	Input  Values
+	Output Values
+	// InputScalar and OutputScalar are the scalar values of Input and Output,
+	// converted once when the sample is inserted, or nil if they couldn't be
+	// converted.
+	InputScalar  *big.Float
+	OutputScalar *big.Float
+	// InputErr and OutputErr are the errors converting Input and Output to
+	// scalar values, if any. The DiskStore keeps only the text of the errors,
+	// and the SQLStore doesn't keep them.
+	InputErr  error
+	OutputErr error
+	// Labels are the labels that the function returned with the output, if
+	// any.
+	Labels Labels
+}
+
+// A SampleStore stores the samples of a ValuesSet. A ValuesSet calls Insert
+// with exclusive access to the store, but may call the other methods
+// concurrently with each other.
+type SampleStore interface {
+	// Insert stores the sample.
+	Insert(s Sample) error
+	// Iterate calls fn with each stored sample until fn returns false.
+	Iterate(fn func(s Sample) bool) error
+	// MinMax returns the smallest and largest input and output scalar values
+	// of the stored samples, or nil if there are none.
+	MinMax() (minInput, maxInput, minOutput, maxOutput *big.Float)
+	// Len returns the number of stored samples.
+	Len() int
+}
+
+// minMax tracks the smallest and largest input and output scalar values of a
+// stream of samples.
+type minMax struct {
+	minInput, maxInput   *big.Float
+	minOutput, maxOutput *big.Float
+}
+
+func (mm *minMax) update(s Sample) {
+	if in := s.InputScalar; in != nil {
+		if mm.minInput == nil || mm.minInput.Cmp(in) == 1 {
+			mm.minInput = in
+		}
+		if mm.maxInput == nil || mm.maxInput.Cmp(in) == -1 {
+			mm.maxInput = in
+		}
+	}
+	if out := s.OutputScalar; out != nil {
+		if mm.minOutput == nil || mm.minOutput.Cmp(out) == 1 {
+			mm.minOutput = out
+		}
+		if mm.maxOutput == nil || mm.maxOutput.Cmp(out) == -1 {
+			mm.maxOutput = out
+		}
+	}
+}
+
+// MemoryStore is the default SampleStore, which keeps the samples in memory.
+type MemoryStore struct {
+	samples []Sample
+	// capacity is the most samples kept, or zero for no limit.
+	capacity int
+	inserted int
+	rng      *rand.Rand
+}
+
+// NewMemoryStore creates a SampleStore that keeps the samples in memory. If
+// capacity is more than zero, at most capacity samples are kept. Once the store
+// is full, each inserted sample replaces a random kept sample so that the kept
+// samples stay a uniform random sample of every inserted sample (reservoir
+// sampling).
+func NewMemoryStore(capacity int) *MemoryStore {
+	return &MemoryStore{
+		samples:  make([]Sample, 0, capacity),
+		capacity: capacity,
+	}
+}
+
+func (ms *MemoryStore) Insert(s Sample) error {
+	ms.inserted++
+	if ms.capacity <= 0 || len(ms.samples) < ms.capacity {
+		ms.samples = append(ms.samples, s)
+		return nil
+	}
+	if ms.rng == nil {
+		ms.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	if i := ms.rng.Intn(ms.inserted); i < ms.capacity {
+		ms.samples[i] = s
+	}
+	return nil
+}
+
+func (ms *MemoryStore) Iterate(fn func(s Sample) bool) error {
+	for _, s := range ms.samples {
+		if !fn(s) {
+			break
+		}
+	}
+	return nil
+}
+
+func (ms *MemoryStore) MinMax() (minInput, maxInput, minOutput, maxOutput *big.Float) {
+	// Kept samples can be replaced, so find the smallest and largest values
+	// of the samples kept now.
+	var mm minMax
+	for _, s := range ms.samples {
+		mm.update(s)
+	}
+	return mm.minInput, mm.maxInput, mm.minOutput, mm.maxOutput
+}
+
+func (ms *MemoryStore) Len() int {
+	return len(ms.samples)
+}
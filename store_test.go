@@ -0,0 +1,78 @@
+package fnplot
+
+import (
+	"io/ioutil"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStoreMinMax(t *testing.T) {
+	set := newTestSet(t, []float64{3, -1, 2}, []float64{5, 7, -4})
+	minInput, maxInput, minOutput, maxOutput := set.MinMax()
+	assert.Zero(t, big.NewFloat(-1).Cmp(minInput), "Expected and actual smallest inputs are different")
+	assert.Zero(t, big.NewFloat(3).Cmp(maxInput), "Expected and actual largest inputs are different")
+	assert.Zero(t, big.NewFloat(-4).Cmp(minOutput), "Expected and actual smallest outputs are different")
+	assert.Zero(t, big.NewFloat(7).Cmp(maxOutput), "Expected and actual largest outputs are different")
+
+	minInput, _, _, _ = (&ValuesSet{}).MinMax()
+	assert.Nil(t, minInput, "Expected no smallest input of an empty set")
+}
+
+func TestDiskStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fnplot")
+	require.NoError(t, err, "Error creating temporary directory")
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "samples.gob")
+
+	store, err := NewDiskStore(filename)
+	require.NoError(t, err, "Error creating disk store")
+	fn := NewFnWithStore(func(x float64) float64 { return 2 * x }, 200, store, Float64Range(0, 10))
+	set := fn.ValuesSet()
+	assert.Equal(t, 200, set.Len(), "Expected and actual number of samples are different")
+
+	inputs, outputs, err := set.scalars()
+	require.NoError(t, err, "Error reading samples")
+	require.Len(t, inputs, 200, "Expected and actual number of samples read are different")
+	for i := range inputs {
+		doubled := big.NewFloat(0).Mul(inputs[i], big.NewFloat(2))
+		assert.Zero(t, doubled.Cmp(outputs[i]), "Expected and actual outputs are different")
+	}
+
+	require.NoError(t, store.Close(), "Error closing disk store")
+	loaded, err := LoadGob(filename)
+	require.NoError(t, err, "Error loading disk store file")
+	assert.Equal(t, 200, loaded.Len(), "Expected and actual number of loaded samples are different")
+}
+
+func TestDiskStoreConversionError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fnplot")
+	require.NoError(t, err, "Error creating temporary directory")
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "samples.gob")
+
+	store, err := NewDiskStore(filename)
+	require.NoError(t, err, "Error creating disk store")
+	set := NewValuesSet(store)
+	require.NoError(t, set.insert(NewValues(1.0), NewValues(2.0)), "Error inserting values")
+	assert.Error(t, set.insert(NewValues(2.0), NewValues(complex(math.NaN(), 0))), "Expected an error converting values")
+
+	_, _, err = set.scalars()
+	require.Error(t, err, "Expected an error reading samples")
+	assert.Contains(t, err.Error(), "has no scalar value", "Expected the conversion error")
+
+	require.NoError(t, store.Close(), "Error closing disk store")
+	loaded, err := LoadGob(filename)
+	require.NoError(t, err, "Error loading disk store file")
+	assert.Equal(t, 2, loaded.Len(), "Expected and actual number of loaded samples are different")
+	_, _, err = loaded.scalars()
+	require.Error(t, err, "Expected an error converting the loaded samples")
+	assert.Equal(t, ErrScalarConversion, errors.Cause(err), "Expected a scalar conversion error")
+	assert.Contains(t, err.Error(), "has no scalar value", "Expected the conversion error")
+}
@@ -0,0 +1,69 @@
+package fnplot
+
+import (
+	"image/color"
+
+	"gonum.org/v1/plot/plotutil"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// SeriesStyle describes how the lines and points of a series are drawn. Zero
+// fields use the default for the index of the series, so the zero SeriesStyle
+// draws each series differently.
+type SeriesStyle struct {
+	// Color is the color of the lines and points of the series. If nil, the
+	// color is from the plot Theme palette.
+	Color color.Color
+	// Dashes is the dash pattern of the line of the series, alternating
+	// lengths of drawn and skipped line. If nil, the plotutil dashes are used.
+	// Use an empty, non-nil slice for a solid line.
+	Dashes []vg.Length
+	// Shape is the glyph drawn at each point of the series, like
+	// draw.CircleGlyph{}. If nil, the plotutil shapes are used.
+	Shape draw.GlyphDrawer
+	// Radius is the radius of the glyph drawn at each point of the series. If
+	// zero, the gonum default radius is used.
+	Radius vg.Length
+}
+
+// resolve returns the style with every zero field set to the default for the
+// series at index i.
+func (style SeriesStyle) resolve(th Theme, i int) SeriesStyle {
+	if style.Color == nil {
+		style.Color = th.color(i)
+	}
+	if style.Dashes == nil {
+		style.Dashes = plotutil.Dashes(i)
+	}
+	if style.Shape == nil {
+		style.Shape = plotutil.Shape(i)
+	}
+	return style
+}
+
+// lineStyle returns the gonum line style of the series.
+func (style SeriesStyle) lineStyle(base draw.LineStyle) draw.LineStyle {
+	base.Color = style.Color
+	base.Dashes = style.Dashes
+	return base
+}
+
+// glyphStyle returns the gonum glyph style of the points of the series.
+func (style SeriesStyle) glyphStyle(base draw.GlyphStyle) draw.GlyphStyle {
+	base.Color = style.Color
+	base.Shape = style.Shape
+	if style.Radius != 0 {
+		base.Radius = style.Radius
+	}
+	return base
+}
+
+// seriesStyles returns the resolved style of each series.
+func seriesStyles(th Theme, series []Series) []SeriesStyle {
+	styles := make([]SeriesStyle, len(series))
+	for i := range series {
+		styles[i] = series[i].Style.resolve(th, i)
+	}
+	return styles
+}
@@ -0,0 +1,94 @@
+package fnplot
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gonum.org/v1/plot/plotutil"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+func TestSeriesStyleResolve(t *testing.T) {
+	red := color.RGBA{R: 0xff, A: 0xff}
+	tests := []struct {
+		description string
+		style       SeriesStyle
+		theme       Theme
+		index       int
+		expected    SeriesStyle
+	}{
+		{
+			description: "defaults",
+			style:       SeriesStyle{},
+			index:       1,
+			expected: SeriesStyle{
+				Color:  plotutil.Color(1),
+				Dashes: plotutil.Dashes(1),
+				Shape:  plotutil.Shape(1),
+			},
+		},
+		{
+			description: "theme palette",
+			style:       SeriesStyle{},
+			theme:       Theme{Palette: []color.Color{red}},
+			index:       1,
+			expected: SeriesStyle{
+				Color:  red,
+				Dashes: plotutil.Dashes(1),
+				Shape:  plotutil.Shape(1),
+			},
+		},
+		{
+			description: "set fields are kept",
+			style: SeriesStyle{
+				Color:  red,
+				Dashes: []vg.Length{},
+				Shape:  draw.CrossGlyph{},
+				Radius: vg.Points(5),
+			},
+			index: 1,
+			expected: SeriesStyle{
+				Color:  red,
+				Dashes: []vg.Length{},
+				Shape:  draw.CrossGlyph{},
+				Radius: vg.Points(5),
+			},
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.style.resolve(test.theme, test.index), "Expected and actual styles are different")
+		})
+	}
+}
+
+func TestPlotStyleRender(t *testing.T) {
+	set := newTestSet(t, []float64{0, 1, 2}, []float64{0, 1, 4})
+	tests := []struct {
+		description string
+		style       PlotStyle
+		expectedErr bool
+	}{
+		{description: "line points", style: LinePoints},
+		{description: "line", style: Line},
+		{description: "scatter", style: Scatter},
+		{description: "unknown style", style: PlotStyle(100), expectedErr: true},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			pl := Plot{Fn: FnOf(set), X: &StdAxix{}, Y: &StdAxix{}, Style: test.style}
+			p, err := pl.render()
+			if test.expectedErr {
+				assert.Error(t, err, "Expected an error rendering the plot")
+				return
+			}
+			require.NoError(t, err, "Error rendering plot")
+			assert.Equal(t, 4.0, p.Y.Max, "Expected the Y axis to include the largest output")
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package fnplot
+
+import (
+	"reflect"
+	"sort"
+)
+
+// TagLabel is the label that a Tag is stored as.
+const TagLabel = "tag"
+
+// A Tag labels a sample, like "cache-hit" or "cache-miss". Return a Tag as the
+// last return value of a sampled function to tag each sample. The Tag isn't
+// part of the output value, and is stored as the TagLabel label of the sample.
+type Tag string
+
+// Labels label a sample with any number of key/value pairs. Return Labels as
+// the last return value of a sampled function to label each sample. The Labels
+// aren't part of the output value.
+type Labels map[string]string
+
+var (
+	tagType    = reflect.TypeOf(Tag(""))
+	labelsType = reflect.TypeOf(Labels(nil))
+)
+
+// splitLabels removes a Tag or Labels last return value from the results of a
+// sampled function and returns the remaining results and the labels.
+func splitLabels(results []reflect.Value) ([]reflect.Value, Labels) {
+	if len(results) == 0 {
+		return results, nil
+	}
+	last := results[len(results)-1]
+	switch last.Type() {
+	case tagType:
+		return results[:len(results)-1], Labels{TagLabel: last.String()}
+	case labelsType:
+		return results[:len(results)-1], last.Interface().(Labels)
+	}
+	return results, nil
+}
+
+// GroupBy returns a new set for each value of the given label of the pairs in
+// the set. Pairs without the label aren't in any of the sets.
+func (set *ValuesSet) GroupBy(label string) (map[string]*ValuesSet, error) {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	samples, err := set.samplesLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string]*ValuesSet)
+	for _, s := range samples {
+		value, ok := s.Labels[label]
+		if !ok {
+			continue
+		}
+		group, ok := groups[value]
+		if !ok {
+			group = &ValuesSet{}
+			groups[value] = group
+		}
+		if err := group.insertSample(s); err != nil {
+			return nil, err
+		}
+	}
+	return groups, nil
+}
+
+// SplitTags returns a series for each value of the given label of the sampled
+// pairs, like TagLabel, named by the label value and sorted by name. Add the
+// series to a Plot to draw each group of pairs in a different color.
+func (fn Fn) SplitTags(label string) ([]Series, error) {
+	groups, err := fn.set.GroupBy(label)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	series := make([]Series, len(names))
+	for i, name := range names {
+		series[i] = Series{Name: name, Fn: FnOf(groups[name])}
+	}
+	return series, nil
+}
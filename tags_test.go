@@ -0,0 +1,56 @@
+package fnplot
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitTags(t *testing.T) {
+	fn := NewFn(func(x float64) (float64, Tag) {
+		if x < 50 {
+			return x, "low"
+		}
+		return x, "high"
+	}, 100, Float64Range(0, 100))
+
+	series, err := fn.SplitTags(TagLabel)
+	require.NoError(t, err, "Error splitting tags")
+	require.Len(t, series, 2, "Expected and actual number of series are different")
+	assert.Equal(t, "high", series[0].Name, "Expected and actual series names are different")
+	assert.Equal(t, "low", series[1].Name, "Expected and actual series names are different")
+
+	high, highOutputs := setFloats(t, series[0].Fn.ValuesSet())
+	low, _ := setFloats(t, series[1].Fn.ValuesSet())
+	assert.Equal(t, 100, len(high)+len(low), "Expected every sample to be in a group")
+	assert.Equal(t, high, highOutputs, "Expected the tag to not be part of the output")
+	for _, x := range high {
+		assert.True(t, x >= 50, "Expected only high inputs in the high group")
+	}
+}
+
+func TestLabelsPersisted(t *testing.T) {
+	fn := NewFn(func(x float64) (float64, Labels) {
+		return x, Labels{"parity": "any"}
+	}, 10, Float64Range(0, 1))
+
+	data, err := json.Marshal(fn.ValuesSet())
+	require.NoError(t, err, "Error encoding JSON")
+	fromJSON := &ValuesSet{}
+	require.NoError(t, json.Unmarshal(data, fromJSON), "Error decoding JSON")
+
+	var buf bytes.Buffer
+	require.NoError(t, fn.ValuesSet().WriteGob(&buf), "Error writing gob")
+	fromGob, err := ReadGob(&buf)
+	require.NoError(t, err, "Error reading gob")
+
+	for _, set := range []*ValuesSet{fromJSON, fromGob} {
+		groups, err := set.GroupBy("parity")
+		require.NoError(t, err, "Error grouping samples")
+		require.Contains(t, groups, "any", "Expected the labels to be persisted")
+		assert.Equal(t, 10, groups["any"].Len(), "Expected and actual number of labeled samples are different")
+	}
+}
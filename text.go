@@ -0,0 +1,205 @@
+package fnplot
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/plot/plotter"
+)
+
+// brailleDots are the bits of the braille character dots in a 2x4 cell,
+// indexed by [y][x].
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// brailleCanvas is a grid of braille characters, each of which has 2x4 dots.
+type brailleCanvas struct {
+	cols, rows int
+	cells      []rune
+}
+
+func newBrailleCanvas(cols, rows int) *brailleCanvas {
+	return &brailleCanvas{cols: cols, rows: rows, cells: make([]rune, cols*rows)}
+}
+
+// set sets the dot at (x, y), where (0, 0) is the top left dot. Dots outside of
+// the canvas are ignored.
+func (bc *brailleCanvas) set(x, y int) {
+	if x < 0 || y < 0 || x >= bc.cols*2 || y >= bc.rows*4 {
+		return
+	}
+	bc.cells[(y/4)*bc.cols+x/2] |= brailleDots[y%4][x%2]
+}
+
+// line sets the dots on the line between (x0, y0) and (x1, y1) using
+// Bresenham's line algorithm.
+func (bc *brailleCanvas) line(x0, y0, x1, y1 int) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		bc.set(x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// row returns a row of the canvas as braille characters.
+func (bc *brailleCanvas) row(r int) string {
+	var sb strings.Builder
+	for _, dots := range bc.cells[r*bc.cols : (r+1)*bc.cols] {
+		sb.WriteRune(0x2800 + dots)
+	}
+	return sb.String()
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// textRange returns the smallest and largest finite X and Y values of the
+// points. Ranges without any width are widened so that points can be scaled.
+func textRange(points []plotter.XYs) (minX, maxX, minY, maxY float64) {
+	minX, minY = math.Inf(1), math.Inf(1)
+	maxX, maxY = math.Inf(-1), math.Inf(-1)
+	for _, pts := range points {
+		for _, p := range pts {
+			if math.IsInf(p.X, 0) || math.IsNaN(p.X) || math.IsInf(p.Y, 0) || math.IsNaN(p.Y) {
+				continue
+			}
+			minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+			minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+		}
+	}
+	if minX == maxX {
+		minX, maxX = minX-0.5, maxX+0.5
+	}
+	if minY == maxY {
+		minY, maxY = minY-0.5, maxY+0.5
+	}
+	return minX, maxX, minY, maxY
+}
+
+// WriteText writes the plot to w as text, drawing the points with braille
+// characters so that plots can be viewed in a terminal or a CI log. The plot
+// area is cols characters wide and rows lines tall. Every series is drawn in
+// the same plot area; the Line and LinePoints styles connect the points of
+// each series with lines and all other styles draw only the points.
+func (pl Plot) WriteText(w io.Writer, cols, rows int) error {
+	if cols <= 0 || rows <= 0 {
+		return errors.New("the text plot size must be greater than zero")
+	}
+	series := pl.series()
+	points, err := pl.seriesPoints(series)
+	if err != nil {
+		return errors.WithMessage(err, "error generating X,Y points")
+	}
+	minX, maxX, minY, maxY := textRange(points)
+	if math.IsInf(minX, 0) {
+		return errors.New("cannot plot text without any finite points")
+	}
+
+	canvas := newBrailleCanvas(cols, rows)
+	dot := func(p plotter.XY) (int, int, bool) {
+		if math.IsInf(p.X, 0) || math.IsNaN(p.X) || math.IsInf(p.Y, 0) || math.IsNaN(p.Y) {
+			return 0, 0, false
+		}
+		x := int(math.Round((p.X - minX) / (maxX - minX) * float64(cols*2-1)))
+		y := int(math.Round((maxY - p.Y) / (maxY - minY) * float64(rows*4-1)))
+		return x, y, true
+	}
+	lines := pl.Style == Line || pl.Style == LinePoints
+	for _, pts := range points {
+		prevX, prevY, prevOK := 0, 0, false
+		for _, p := range pts {
+			x, y, ok := dot(p)
+			if ok && lines && prevOK {
+				canvas.line(prevX, prevY, x, y)
+			} else if ok {
+				canvas.set(x, y)
+			}
+			prevX, prevY, prevOK = x, y, ok
+		}
+	}
+
+	format := func(f TickFormatter, v float64) string {
+		if f != nil {
+			return f(v)
+		}
+		return strconv.FormatFloat(v, 'g', 4, 64)
+	}
+	top, bottom := format(pl.YTickFormat, maxY), format(pl.YTickFormat, minY)
+	labelWidth := utf8.RuneCountInString(top)
+	if n := utf8.RuneCountInString(bottom); n > labelWidth {
+		labelWidth = n
+	}
+	// spaces returns n spaces, or none if n is negative because a title or
+	// label is wider than the space for it.
+	spaces := func(n int) string {
+		if n < 0 {
+			n = 0
+		}
+		return strings.Repeat(" ", n)
+	}
+	pad := func(s string, n int) string {
+		return spaces(n-utf8.RuneCountInString(s)) + s
+	}
+
+	bw := bufio.NewWriter(w)
+	if pl.Title != "" {
+		bw.WriteString(pad(pl.Title, labelWidth+2+(cols+utf8.RuneCountInString(pl.Title))/2) + "\n")
+	}
+	for r := 0; r < rows; r++ {
+		label, axis := "", "│"
+		switch r {
+		case 0:
+			label, axis = top, "┤"
+		case rows - 1:
+			label, axis = bottom, "┤"
+		}
+		bw.WriteString(pad(label, labelWidth) + " " + axis + canvas.row(r) + "\n")
+	}
+	bw.WriteString(spaces(labelWidth+1) + "└" + strings.Repeat("─", cols) + "\n")
+	left, right := format(pl.XTickFormat, minX), format(pl.XTickFormat, maxX)
+	gap := cols - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+	if gap < 1 {
+		gap = 1
+	}
+	bw.WriteString(spaces(labelWidth+2) + left + spaces(gap) + right + "\n")
+	if len(series) > 1 {
+		names := make([]string, len(series))
+		for i := range series {
+			names[i] = series[i].Name
+		}
+		bw.WriteString(spaces(labelWidth+2) + strings.Join(names, ", ") + "\n")
+	}
+	return errors.WithMessage(bw.Flush(), "error writing text plot")
+}
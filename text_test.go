@@ -0,0 +1,53 @@
+package fnplot
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrailleCanvas(t *testing.T) {
+	canvas := newBrailleCanvas(2, 1)
+	canvas.set(0, 0)
+	canvas.set(3, 3)
+	canvas.set(4, 0) // Outside of the canvas.
+	assert.Equal(t, "⠁⢀", canvas.row(0), "Expected and actual rows are different")
+
+	canvas = newBrailleCanvas(2, 1)
+	canvas.line(0, 3, 3, 0)
+	assert.Equal(t, "⡠⠊", canvas.row(0), "Expected and actual rows are different")
+}
+
+func TestWriteTextTitle(t *testing.T) {
+	set := newTestSet(t, []float64{0, 1, 2}, []float64{0, 1, 4})
+	tests := []struct {
+		description string
+		title       string
+		expected    string
+	}{
+		{
+			description: "short title",
+			title:       "squares",
+			expected:    "         squares",
+		},
+		{
+			description: "title wider than the plot",
+			title:       "the squares of the first three whole numbers",
+			expected:    "the squares of the first three whole numbers",
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			pl := Plot{Fn: FnOf(set), X: &StdAxix{}, Y: &StdAxix{}, Title: test.title}
+			var buf bytes.Buffer
+			require.NoError(t, pl.WriteText(&buf, 20, 5), "Error writing text plot")
+			lines := strings.Split(buf.String(), "\n")
+			assert.Equal(t, test.expected, lines[0], "Expected and actual title lines are different")
+			assert.Len(t, lines, 9, "Expected the title, the plot rows, the axis, and the X labels")
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package fnplot
+
+import (
+	"image/color"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/plotutil"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Theme describes the colors and fonts of a plot. Zero fields keep the gonum
+// defaults, so the zero Theme draws a plot the same as having no theme.
+type Theme struct {
+	// Background is the color behind the plot.
+	Background color.Color
+	// Foreground is the color of the title, axes, tick marks, labels, and
+	// legend text.
+	Foreground color.Color
+	// Grid is the color of the grid lines drawn behind the series. If Grid is
+	// nil, no grid is drawn.
+	Grid color.Color
+	// Palette is the colors of the series, in order. The colors repeat if there
+	// are more series than colors. If Palette is empty, the plotutil default
+	// colors are used.
+	Palette []color.Color
+	// Font is the name of the font used for all text, like "Helvetica" or
+	// "Times-Roman". The font sizes are not changed.
+	Font string
+}
+
+// LightTheme is a light gray grid and dark text on a white background.
+var LightTheme = Theme{
+	Background: color.White,
+	Foreground: color.Black,
+	Grid:       color.Gray{Y: 0xdd},
+	Palette:    plotutil.DarkColors,
+	Font:       "Helvetica",
+}
+
+// DarkTheme is a dim grid and light text on a dark gray background, for
+// embedding plots in pages with a dark background.
+var DarkTheme = Theme{
+	Background: color.RGBA{R: 0x1e, G: 0x1e, B: 0x1e, A: 0xff},
+	Foreground: color.RGBA{R: 0xd4, G: 0xd4, B: 0xd4, A: 0xff},
+	Grid:       color.RGBA{R: 0x3c, G: 0x3c, B: 0x3c, A: 0xff},
+	Palette: []color.Color{
+		color.RGBA{R: 0x4f, G: 0xc3, B: 0xf7, A: 0xff},
+		color.RGBA{R: 0xff, G: 0xb7, B: 0x4d, A: 0xff},
+		color.RGBA{R: 0x81, G: 0xc7, B: 0x84, A: 0xff},
+		color.RGBA{R: 0xf0, G: 0x62, B: 0x92, A: 0xff},
+		color.RGBA{R: 0xba, G: 0x68, B: 0xc8, A: 0xff},
+		color.RGBA{R: 0xff, G: 0xf1, B: 0x76, A: 0xff},
+		color.RGBA{R: 0x4d, G: 0xb6, B: 0xac, A: 0xff},
+	},
+	Font: "Helvetica",
+}
+
+// HighContrastTheme is saturated series colors and white text on a black
+// background with no grid.
+var HighContrastTheme = Theme{
+	Background: color.Black,
+	Foreground: color.White,
+	Palette: []color.Color{
+		color.RGBA{R: 0xff, G: 0xff, A: 0xff},
+		color.RGBA{G: 0xff, B: 0xff, A: 0xff},
+		color.RGBA{R: 0xff, B: 0xff, A: 0xff},
+		color.RGBA{G: 0xff, A: 0xff},
+		color.RGBA{R: 0xff, G: 0x80, A: 0xff},
+		color.White,
+	},
+	Font: "Helvetica-Bold",
+}
+
+// color returns the color of the series at index i.
+func (th Theme) color(i int) color.Color {
+	if len(th.Palette) == 0 {
+		return plotutil.Color(i)
+	}
+	return th.Palette[i%len(th.Palette)]
+}
+
+// apply styles the plot with the theme. It must be called before any series
+// are added so that the grid is drawn behind them.
+func (th Theme) apply(p *plot.Plot) error {
+	if th.Background != nil {
+		p.BackgroundColor = th.Background
+	}
+	if th.Foreground != nil {
+		for _, axis := range []*plot.Axis{&p.X, &p.Y} {
+			axis.Color = th.Foreground
+			axis.Label.Color = th.Foreground
+			axis.Tick.Color = th.Foreground
+			axis.Tick.Label.Color = th.Foreground
+		}
+		p.Title.Color = th.Foreground
+		p.Legend.Color = th.Foreground
+	}
+	if th.Font != "" {
+		styles := []*draw.TextStyle{
+			&p.Title.TextStyle,
+			&p.X.Label.TextStyle,
+			&p.Y.Label.TextStyle,
+			&p.X.Tick.Label,
+			&p.Y.Tick.Label,
+			&p.Legend.TextStyle,
+		}
+		for _, style := range styles {
+			font, err := vg.MakeFont(th.Font, style.Font.Size)
+			if err != nil {
+				return errors.WithMessage(err, "error loading theme font "+th.Font)
+			}
+			style.Font = font
+		}
+	}
+	if th.Grid != nil {
+		grid := plotter.NewGrid()
+		grid.Vertical.Color = th.Grid
+		grid.Horizontal.Color = th.Grid
+		p.Add(grid)
+	}
+	return nil
+}
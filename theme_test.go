@@ -0,0 +1,62 @@
+package fnplot
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotutil"
+)
+
+func TestThemeColor(t *testing.T) {
+	red := color.RGBA{R: 0xff, A: 0xff}
+	blue := color.RGBA{B: 0xff, A: 0xff}
+	tests := []struct {
+		description string
+		theme       Theme
+		index       int
+		expected    color.Color
+	}{
+		{
+			description: "default palette",
+			theme:       Theme{},
+			index:       1,
+			expected:    plotutil.Color(1),
+		},
+		{
+			description: "theme palette",
+			theme:       Theme{Palette: []color.Color{red, blue}},
+			index:       1,
+			expected:    blue,
+		},
+		{
+			description: "theme palette repeats",
+			theme:       Theme{Palette: []color.Color{red, blue}},
+			index:       2,
+			expected:    red,
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.theme.color(test.index), "Expected and actual colors are different")
+		})
+	}
+}
+
+func TestThemeApply(t *testing.T) {
+	p, err := plot.New()
+	require.NoError(t, err, "Error creating plot")
+	require.NoError(t, DarkTheme.apply(p), "Error applying theme")
+
+	assert.Equal(t, DarkTheme.Background, p.BackgroundColor, "Expected and actual background colors are different")
+	assert.Equal(t, DarkTheme.Foreground, p.Title.Color, "Expected and actual title colors are different")
+	assert.Equal(t, DarkTheme.Foreground, p.X.Tick.Label.Color, "Expected and actual tick label colors are different")
+	assert.Equal(t, "Helvetica", p.Legend.Font.Name(), "Expected and actual legend fonts are different")
+
+	p, err = plot.New()
+	require.NoError(t, err, "Error creating plot")
+	assert.Error(t, Theme{Font: "NoSuchFont"}.apply(p), "Expected an error for an unknown font")
+}
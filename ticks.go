@@ -0,0 +1,65 @@
+package fnplot
+
+import (
+	"math"
+	"strconv"
+
+	"gonum.org/v1/plot"
+)
+
+// A TickFormatter converts the value of an axis tick mark to its label.
+type TickFormatter func(float64) string
+
+// siPrefixes are the metric prefixes used by HumanizedTicks, in increasing
+// powers of 1000.
+var siPrefixes = []string{"", "k", "M", "G", "T", "P", "E", "Z", "Y"}
+
+// HumanizedTicks formats tick values with metric prefixes, like "1.2M" or
+// "3.4G". Values too large for a metric prefix are formatted in scientific
+// notation.
+func HumanizedTicks(v float64) string {
+	exp := 0
+	if abs := math.Abs(v); abs >= 1000 {
+		exp = int(math.Log10(abs) / 3)
+	}
+	m := v / math.Pow(1000, float64(exp))
+	// Rounding to 3 significant digits can carry into the next prefix, like
+	// 999.9k to 1M.
+	if rounded, _ := strconv.ParseFloat(strconv.FormatFloat(m, 'g', 3, 64), 64); math.Abs(rounded) >= 1000 {
+		exp++
+		m /= 1000
+	}
+	if exp >= len(siPrefixes) {
+		return ScientificTicks(v)
+	}
+	return strconv.FormatFloat(m, 'g', 3, 64) + siPrefixes[exp]
+}
+
+// ScientificTicks formats tick values in scientific notation, like "1.2e+06".
+func ScientificTicks(v float64) string {
+	return strconv.FormatFloat(v, 'e', -1, 64)
+}
+
+// tickerFor returns the plot.Ticker for the given axis. If the axis implements
+// plot.Ticker, it is used to place the tick marks. Otherwise, the default
+// gonum tick marks are used. If format is not nil, it replaces the labels of
+// the tick marks.
+func tickerFor(axis Axis, format TickFormatter) plot.Ticker {
+	ticker, ok := axis.(plot.Ticker)
+	if !ok {
+		ticker = plot.DefaultTicks{}
+	}
+	if format == nil {
+		return ticker
+	}
+	return plot.TickerFunc(func(min, max float64) []plot.Tick {
+		ticks := ticker.Ticks(min, max)
+		for i := range ticks {
+			if ticks[i].Label == "" {
+				continue
+			}
+			ticks[i].Label = format(ticks[i].Value)
+		}
+		return ticks
+	})
+}
@@ -0,0 +1,31 @@
+package fnplot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHumanizedTicks(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected string
+	}{
+		{value: 0, expected: "0"},
+		{value: 12.5, expected: "12.5"},
+		{value: -999, expected: "-999"},
+		{value: 999.9, expected: "1k"},
+		{value: 1000, expected: "1k"},
+		{value: 999999, expected: "1M"},
+		{value: 1.2e6, expected: "1.2M"},
+		{value: -3.4e9, expected: "-3.4G"},
+		{value: 5e24, expected: "5Y"},
+		{value: 5e30, expected: "5e+30"},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.expected, func(t *testing.T) {
+			assert.Equal(t, test.expected, HumanizedTicks(test.value), "Expected and actual labels are different")
+		})
+	}
+}
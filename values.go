@@ -2,12 +2,18 @@ package fnplot
 
 import (
 	"bytes"
+	"encoding"
 	"encoding/binary"
 	"fmt"
 	"math"
 	"math/big"
+	"math/cmplx"
 	"reflect"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -24,6 +30,144 @@ func NewValues(args ...interface{}) Values {
 	return values
 }
 
+// String formats the values separated by commas.
+func (vs Values) String() string {
+	formatted := make([]string, len(vs))
+	for i := range vs {
+		if !vs[i].IsValid() || !vs[i].CanInterface() {
+			formatted[i] = "<nil>"
+			continue
+		}
+		formatted[i] = fmt.Sprintf("%v", indirect(vs[i]).Interface())
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// A Scalarizer is a value that converts itself to a scalar value. Values.Scalar
+// uses ToScalar instead of the binary representation of values that implement
+// Scalarizer, like custom decimal or tree types.
+type Scalarizer interface {
+	ToScalar() *big.Float
+}
+
+var (
+	scalarFuncs   = make(map[reflect.Type]func(interface{}) (*big.Float, error))
+	scalarFuncsMu sync.RWMutex
+)
+
+// RegisterScalar registers a function that converts values of the given type to
+// a scalar value. Use RegisterScalar for types that can't implement Scalarizer,
+// like types from other packages. Registered functions take precedence over
+// Scalarizer and are also used for pointers to values of the given type.
+// Registering a nil function removes the registered function for the type.
+func RegisterScalar(typ reflect.Type, fn func(interface{}) (*big.Float, error)) {
+	scalarFuncsMu.Lock()
+	defer scalarFuncsMu.Unlock()
+
+	if fn == nil {
+		delete(scalarFuncs, typ)
+		return
+	}
+	scalarFuncs[typ] = fn
+}
+
+// registeredScalar returns the function registered for the type, if any.
+func registeredScalar(typ reflect.Type) func(interface{}) (*big.Float, error) {
+	scalarFuncsMu.RLock()
+	defer scalarFuncsMu.RUnlock()
+	return scalarFuncs[typ]
+}
+
+// customScalar returns the scalar value of the value if its type has a custom
+// conversion to a scalar value, either a registered function or Scalarizer.
+func customScalar(value reflect.Value) (*big.Float, bool, error) {
+	if !value.IsValid() || !value.CanInterface() {
+		return nil, false, nil
+	}
+	if value.Kind() == reflect.Ptr && value.IsNil() {
+		return nil, false, nil
+	}
+	if fn := registeredScalar(value.Type()); fn != nil {
+		f, err := fn(value.Interface())
+		return f, true, errors.WithMessage(err, "error converting "+value.Type().String()+" to a scalar value")
+	}
+	if value.Kind() == reflect.Ptr {
+		if fn := registeredScalar(value.Type().Elem()); fn != nil {
+			f, err := fn(value.Elem().Interface())
+			return f, true, errors.WithMessage(err, "error converting "+value.Type().Elem().String()+" to a scalar value")
+		}
+	}
+	if s, ok := value.Interface().(Scalarizer); ok {
+		return s.ToScalar(), true, nil
+	}
+	return nil, false, nil
+}
+
+// bigScalar returns the value as a *big.Float at full precision if it's a
+// *big.Int, *big.Rat, or *big.Float, or one of those types.
+func bigScalar(value reflect.Value) (*big.Float, bool) {
+	value = indirect(value)
+	if !value.IsValid() || !value.CanInterface() {
+		return nil, false
+	}
+	switch value.Type() {
+	case reflect.TypeOf(big.Int{}), reflect.TypeOf(big.Rat{}), reflect.TypeOf(big.Float{}):
+	default:
+		return nil, false
+	}
+	switch v := addressable(value).Addr().Interface().(type) {
+	case *big.Int:
+		return new(big.Float).SetInt(v), true
+	case *big.Rat:
+		return new(big.Float).SetRat(v), true
+	case *big.Float:
+		return new(big.Float).Copy(v), true
+	}
+	return nil, false
+}
+
+// marshaled returns the binary representation of the value if it implements
+// encoding.BinaryMarshaler, or its text representation if it implements
+// encoding.TextMarshaler.
+func marshaled(value reflect.Value) ([]byte, bool, error) {
+	if !value.CanInterface() {
+		return nil, false, nil
+	}
+	// Marshalers usually have pointer receivers, so marshal a pointer to the
+	// value.
+	switch m := addressable(value).Addr().Interface().(type) {
+	case encoding.BinaryMarshaler:
+		b, err := m.MarshalBinary()
+		return b, true, errors.WithMessage(err, "error marshaling value to binary")
+	case encoding.TextMarshaler:
+		b, err := m.MarshalText()
+		return b, true, errors.WithMessage(err, "error marshaling value to text")
+	}
+	return nil, false, nil
+}
+
+// writeMarshaled writes the marshaled representation of the value to the
+// buffer, returning false if the value isn't a marshaler.
+func writeMarshaled(buf *bytes.Buffer, value reflect.Value) (bool, error) {
+	b, ok, err := marshaled(value)
+	if !ok || err != nil {
+		return ok, err
+	}
+	_, err = buf.Write(b)
+	return true, errors.WithMessage(err, "error writing marshaled value to writer")
+}
+
+// addressable returns the value if it's addressable, or an addressable copy of
+// the value so that methods with pointer receivers can be called on it.
+func addressable(value reflect.Value) reflect.Value {
+	if value.CanAddr() {
+		return value
+	}
+	ptr := reflect.New(value.Type())
+	ptr.Elem().Set(value)
+	return ptr.Elem()
+}
+
 // smallestInt returns the smallest fixed-size signed or unsigned integer value
 // necessary to store the given variable-size signed integer value.
 func smallestInt(x int) interface{} {
@@ -60,9 +204,45 @@ func writeBinary(buf *bytes.Buffer, value reflect.Value) error {
 		return nil
 	}
 
+	// Write values with a custom scalar value as the binary representation of
+	// the scalar value as a float64, like any other float64 value.
+	custom, ok, err := customScalar(value)
+	if err != nil {
+		return err
+	}
+	if ok {
+		f, _ := custom.Float64()
+		err := binary.Write(buf, binary.BigEndian, f)
+		return errors.WithMessage(err, "error writing custom scalar value to writer")
+	}
+
 	value = indirect(value)
+	if !value.IsValid() {
+		// Nil pointers have no binary representation.
+		return nil
+	}
+
+	// Write big integers as their absolute value and other big numbers as the
+	// binary representation of their float64 value.
+	if f, ok := bigScalar(value); ok {
+		if f.IsInt() {
+			x, _ := f.Int(nil)
+			_, err := buf.Write(x.Bytes())
+			return errors.WithMessage(err, "error writing big integer to writer")
+		}
+		f64, _ := f.Float64()
+		err := binary.Write(buf, binary.BigEndian, f64)
+		return errors.WithMessage(err, "error writing big number to writer")
+	}
 
-	// Unpack slice, array, and map types.
+	// Write times as the number of nanoseconds since the Unix epoch instead of
+	// unpacking the time struct, which has no exported fields.
+	if value.Type() == reflect.TypeOf(time.Time{}) && value.CanInterface() {
+		err := binary.Write(buf, binary.BigEndian, value.Interface().(time.Time).UnixNano())
+		return errors.WithMessage(err, "error writing time to writer")
+	}
+
+	// Unpack slice, array, map, and struct types.
 	switch value.Type().Kind() {
 	case reflect.Slice, reflect.Array:
 		for i := 0; i < value.Len(); i++ {
@@ -75,19 +255,57 @@ func writeBinary(buf *bytes.Buffer, value reflect.Value) error {
 		}
 		return nil
 	case reflect.Map:
+		// Map keys are iterated in random order, so write the entries sorted by
+		// the binary representation of their keys to get the same scalar value
+		// for equal maps.
+		type entry struct{ key, value []byte }
+		entries := make([]entry, 0, value.Len())
 		for _, mapKey := range value.MapKeys() {
-			err := writeBinary(buf, mapKey)
+			keyBuf := bytes.NewBuffer(nil)
+			err := writeBinary(keyBuf, mapKey)
 			if err != nil {
 				return errors.WithMessage(
 					err,
 					"error writing binary for map key "+mapKey.String())
 			}
-			err = writeBinary(buf, value.MapIndex(mapKey))
+			valueBuf := bytes.NewBuffer(nil)
+			err = writeBinary(valueBuf, value.MapIndex(mapKey))
 			if err != nil {
 				return errors.WithMessage(
 					err,
 					"error writing binary for map value at key "+mapKey.String())
 			}
+			entries = append(entries, entry{key: keyBuf.Bytes(), value: valueBuf.Bytes()})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return bytes.Compare(entries[i].key, entries[j].key) == -1
+		})
+		for _, e := range entries {
+			buf.Write(e.key)
+			buf.Write(e.value)
+		}
+		return nil
+	case reflect.Struct:
+		// Write structs that marshal themselves as their marshaled
+		// representation, since their exported fields may not represent them.
+		if ok, err := writeMarshaled(buf, value); ok {
+			return err
+		}
+
+		// Only the exported fields of other structs are written, skipping any
+		// fields tagged with `fnplot:"-"`.
+		typ := value.Type()
+		for i := 0; i < value.NumField(); i++ {
+			field := typ.Field(i)
+			if field.PkgPath != "" || field.Tag.Get("fnplot") == "-" {
+				continue
+			}
+			err := writeBinary(buf, value.Field(i))
+			if err != nil {
+				return errors.WithMessage(
+					err,
+					"error writing binary for struct field "+field.Name)
+			}
 		}
 		return nil
 	}
@@ -108,6 +326,13 @@ func writeBinary(buf *bytes.Buffer, value reflect.Value) error {
 	// bit width integer that can hold the value because binary.Write can't
 	// handle "int" and "uint" types because they can be variable bit widths.
 	switch v := iValue.(type) {
+	case bool:
+		var b byte
+		if v {
+			b = 1
+		}
+		err := buf.WriteByte(b)
+		return errors.WithMessage(err, "error writing bool to writer")
 	case byte:
 		err := buf.WriteByte(v)
 		return errors.WithMessage(err, "error writing byte to writer")
@@ -126,7 +351,14 @@ func writeBinary(buf *bytes.Buffer, value reflect.Value) error {
 		iValue = smallestUint(v)
 	}
 
-	err := binary.Write(buf, binary.BigEndian, iValue)
+	err = binary.Write(buf, binary.BigEndian, iValue)
+	if err != nil {
+		// Before giving up on the type, write it as its marshaled
+		// representation if it has one.
+		if ok, mErr := writeMarshaled(buf, value); ok {
+			return mErr
+		}
+	}
 	return errors.WithMessage(
 		err,
 		fmt.Sprintf("error converting value to binary: %#v", value))
@@ -136,12 +368,26 @@ func writeBinary(buf *bytes.Buffer, value reflect.Value) error {
 // scalar value conversion depends on the type of input value.
 //
 // Individual values that are already scalar values (floats and ints) are returned
-// as their original value.
+// as their original value, and individual big.Int, big.Rat, and big.Float values
+// keep their full precision. Individual bools are converted to 0 (false) or 1
+// (true), so predicates are plotted as steps, and bools in collections are
+// written as a 0 or 1 byte. Individual complex numbers are converted to their
+// absolute value (use ComplexScalar to choose another part). Individual times
+// are converted to the number of nanoseconds since the Unix epoch and individual
+// durations to their number of nanoseconds. Individual values with a function
+// registered with RegisterScalar or that implement Scalarizer are converted with
+// that function.
+//
+// Collections of values (slices, arrays, and maps) and the exported fields of
+// structs are unpacked into individual values. Map entries are sorted by the
+// binary representation of their keys, and struct fields tagged with
+// `fnplot:"-"` are skipped. All individual values are converted to their binary
+// representation and appended to a byte slice. When all values are appended to
+// the byte buffer, the bytes are interpreted as a big-endian integer value.
 //
-// Collections of values (slices, arrays, and maps) are unpacked into individual
-// values. All individual values are converted to their binary representation and
-// appended to a byte slice. When all values are appended to the byte buffer, the
-// bytes are interpreted as a big-endian integer value.
+// Structs, and other values that have no binary representation, that implement
+// encoding.BinaryMarshaler or encoding.TextMarshaler are converted to their
+// marshaled representation instead.
 func (vs Values) Scalar() (*big.Float, error) {
 	// Return the zero value of a *big.Float if the input is empty.
 	if len(vs) == 0 {
@@ -153,21 +399,76 @@ func (vs Values) Scalar() (*big.Float, error) {
 		if !vs[0].IsValid() {
 			return big.NewFloat(0), nil
 		}
+		custom, ok, err := customScalar(vs[0])
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return custom, nil
+		}
+		if f, ok := bigScalar(vs[0]); ok {
+			return f, nil
+		}
 		value := indirect(vs[0])
 		if value.Kind() == reflect.Float32 || value.Kind() == reflect.Float64 {
 			return big.NewFloat(value.Float()), nil
 		}
+		if value.Kind() == reflect.Bool {
+			if value.Bool() {
+				return big.NewFloat(1), nil
+			}
+			return big.NewFloat(0), nil
+		}
+		if value.Kind() == reflect.Complex64 || value.Kind() == reflect.Complex128 {
+			c := value.Complex()
+			abs := cmplx.Abs(c)
+			if math.IsNaN(abs) {
+				return nil, errors.Errorf("complex number %v has no scalar value", c)
+			}
+			return big.NewFloat(abs), nil
+		}
+		if value.IsValid() && value.CanInterface() {
+			switch v := value.Interface().(type) {
+			case time.Time:
+				return new(big.Float).SetInt64(v.UnixNano()), nil
+			case time.Duration:
+				return new(big.Float).SetInt64(int64(v)), nil
+			}
+		}
 	}
 
 	// Convert everything else into bytes, interpret those bytes as a variable
 	// precision integer, and return that integer represented as a *big.Float
+	b, err := vs.binary()
+	if err != nil {
+		return nil, err
+	}
+	return newFloat().SetInt(big.NewInt(0).SetBytes(b)), nil
+}
+
+// ScalarOf converts only the values at the given indices to a scalar value, like
+// Scalar converts all values. Use ScalarOf to plot one argument of a function
+// with multiple arguments, like the size argument of fn(n int, s string).
+func (vs Values) ScalarOf(indices ...int) (*big.Float, error) {
+	selected := make(Values, len(indices))
+	for i, index := range indices {
+		if index < 0 || index >= len(vs) {
+			return nil, errors.Errorf("index %d out of range of %d values", index, len(vs))
+		}
+		selected[i] = vs[index]
+	}
+	return selected.Scalar()
+}
+
+// binary returns the binary representation of all values appended together.
+func (vs Values) binary() ([]byte, error) {
 	buf := bytes.NewBuffer(nil)
 	for _, value := range vs {
 		if err := writeBinary(buf, value); err != nil {
 			return nil, errors.WithMessage(err, "error writing values as binary")
 		}
 	}
-	return big.NewFloat(0).SetInt(big.NewInt(0).SetBytes(buf.Bytes())), nil
+	return buf.Bytes(), nil
 }
 
 func indirect(v reflect.Value) reflect.Value {
@@ -3,12 +3,38 @@ package fnplot
 import (
 	"math"
 	"math/big"
+	"reflect"
 	"testing"
+	"time"
+
+	"github.com/pkg/errors"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+type testScalarizer struct {
+	value float64
+}
+
+func (ts testScalarizer) ToScalar() *big.Float {
+	return big.NewFloat(ts.value)
+}
+
+type testBinaryMarshaler struct {
+	b []byte
+}
+
+func (tm *testBinaryMarshaler) MarshalBinary() ([]byte, error) {
+	return tm.b, nil
+}
+
+type testTextMarshaler func()
+
+func (tm testTextMarshaler) MarshalText() ([]byte, error) {
+	return []byte("a"), nil
+}
+
 func TestScalar(t *testing.T) {
 	tests := []struct {
 		description string
@@ -70,6 +96,128 @@ func TestScalar(t *testing.T) {
 			values:      NewValues(map[string]int{"a": 1}),
 			expected:    big.NewFloat(24833),
 		},
+		{
+			description: "false value",
+			values:      NewValues(false),
+			expected:    big.NewFloat(0),
+		},
+		{
+			description: "true value",
+			values:      NewValues(true),
+			expected:    big.NewFloat(1),
+		},
+		{
+			description: "bools in a slice",
+			values:      NewValues([]bool{true, false, true}),
+			expected:    big.NewFloat(0x010001),
+		},
+		{
+			description: "map value with multiple keys",
+			values:      NewValues(map[string]int{"b": 2, "a": 1, "c": 3}),
+			expected:    big.NewFloat(0x610162026303),
+		},
+		{
+			description: "Scalarizer value",
+			values:      NewValues(testScalarizer{value: 2.5}),
+			expected:    big.NewFloat(2.5),
+		},
+		{
+			description: "Scalarizer pointer value",
+			values:      NewValues(&testScalarizer{value: 2.5}),
+			expected:    big.NewFloat(2.5),
+		},
+		{
+			description: "Scalarizer in a slice",
+			values:      NewValues([]testScalarizer{{value: 1}}),
+			// The binary representation of float64(1).
+			expected: big.NewFloat(0x3ff0000000000000),
+		},
+		{
+			description: "struct value",
+			values: NewValues(struct {
+				A string
+				B byte
+			}{A: "a", B: 1}),
+			expected: big.NewFloat(24833),
+		},
+		{
+			description: "struct value with skipped fields",
+			values: NewValues(struct {
+				A       string
+				B       byte
+				Skipped string `fnplot:"-"`
+				private string
+			}{A: "a", B: 1, Skipped: "skipped", private: "private"}),
+			expected: big.NewFloat(24833),
+		},
+		{
+			description: "struct value with nil pointer field",
+			values: NewValues(struct {
+				A string
+				B *byte
+			}{A: "a"}),
+			expected: big.NewFloat(97),
+		},
+		{
+			description: "time value",
+			values:      NewValues(time.Unix(1, 5)),
+			expected:    new(big.Float).SetInt64(1000000005),
+		},
+		{
+			description: "time value before the Unix epoch",
+			values:      NewValues(time.Unix(-1, 0)),
+			expected:    new(big.Float).SetInt64(-1000000000),
+		},
+		{
+			description: "duration value",
+			values:      NewValues(-time.Second),
+			expected:    new(big.Float).SetInt64(-1000000000),
+		},
+		{
+			description: "time in a slice",
+			values:      NewValues([]time.Time{time.Unix(0, 1)}),
+			expected:    big.NewFloat(1),
+		},
+		{
+			description: "big.Int pointer value",
+			values:      NewValues(new(big.Int).Lsh(big.NewInt(1), 100)),
+			expected:    new(big.Float).SetInt(new(big.Int).Lsh(big.NewInt(1), 100)),
+		},
+		{
+			description: "big.Int value",
+			values:      NewValues(*big.NewInt(-7)),
+			expected:    new(big.Float).SetInt(big.NewInt(-7)),
+		},
+		{
+			description: "big.Rat value",
+			values:      NewValues(big.NewRat(1, 4)),
+			expected:    new(big.Float).SetRat(big.NewRat(1, 4)),
+		},
+		{
+			description: "big.Float value",
+			values:      NewValues(new(big.Float).SetPrec(200).SetInt64(3)),
+			expected:    new(big.Float).SetPrec(200).SetInt64(3),
+		},
+		{
+			description: "big.Int in a slice",
+			values:      NewValues([]*big.Int{big.NewInt(258)}),
+			expected:    big.NewFloat(258),
+		},
+		{
+			description: "complex value",
+			values:      NewValues(complex(3, 4)),
+			expected:    big.NewFloat(5),
+		},
+		{
+			description: "BinaryMarshaler struct value",
+			values:      NewValues(testBinaryMarshaler{b: []byte{1, 2}}),
+			expected:    big.NewFloat(258),
+		},
+		{
+			description: "TextMarshaler value without a binary representation",
+			values:      NewValues(testTextMarshaler(nil)),
+			expected:    big.NewFloat(97),
+		},
 	}
 	for _, test := range tests {
 		test := test // Capture range variable.
@@ -80,3 +228,116 @@ func TestScalar(t *testing.T) {
 		})
 	}
 }
+
+func TestScalarComplexNaN(t *testing.T) {
+	_, err := NewValues(complex(math.NaN(), 1)).Scalar()
+	assert.Error(t, err, "Expected an error calculating the scalar value of a NaN complex number")
+
+	fn := NewFn(func(x float64) complex128 { return complex(math.NaN(), x) }, 10, Float64Range(0, 1))
+	assert.Equal(t, 10, fn.ValuesSet().Len(), "Expected a sample for each input")
+	_, _, err = fn.ValuesSet().scalars()
+	assert.Equal(t, ErrScalarConversion, errors.Cause(err), "Expected a scalar conversion error")
+}
+
+type testRegistered struct {
+	value float64
+}
+
+func TestRegisterScalar(t *testing.T) {
+	typ := reflect.TypeOf(testRegistered{})
+	RegisterScalar(typ, func(v interface{}) (*big.Float, error) {
+		r := v.(testRegistered)
+		if r.value < 0 {
+			return nil, errors.New("negative value")
+		}
+		return big.NewFloat(r.value), nil
+	})
+	defer RegisterScalar(typ, nil)
+
+	tests := []struct {
+		description string
+		values      Values
+		expected    *big.Float
+		expectedErr bool
+	}{
+		{
+			description: "registered value",
+			values:      NewValues(testRegistered{value: 2.5}),
+			expected:    big.NewFloat(2.5),
+		},
+		{
+			description: "registered pointer value",
+			values:      NewValues(&testRegistered{value: 2.5}),
+			expected:    big.NewFloat(2.5),
+		},
+		{
+			description: "registered value in a slice",
+			values:      NewValues([]testRegistered{{value: 1}}),
+			// The binary representation of float64(1).
+			expected: big.NewFloat(0x3ff0000000000000),
+		},
+		{
+			description: "registered function error",
+			values:      NewValues(testRegistered{value: -1}),
+			expectedErr: true,
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			s, err := test.values.Scalar()
+			if test.expectedErr {
+				assert.Error(t, err, "Expected an error calculating scalar value")
+				return
+			}
+			require.NoError(t, err, "Error calculating scalar value")
+			assert.Equal(t, test.expected, s, "Expected and actual values are different")
+		})
+	}
+}
+
+func TestScalarOf(t *testing.T) {
+	tests := []struct {
+		description string
+		values      Values
+		indices     []int
+		expected    *big.Float
+		expectedErr bool
+	}{
+		{
+			description: "first value",
+			values:      NewValues(2.5, "a"),
+			indices:     []int{0},
+			expected:    big.NewFloat(2.5),
+		},
+		{
+			description: "multiple values",
+			values:      NewValues("a", 2.5, byte(1)),
+			indices:     []int{2, 0},
+			expected:    big.NewFloat(0x0161),
+		},
+		{
+			description: "no values",
+			values:      NewValues("a"),
+			expected:    big.NewFloat(0),
+		},
+		{
+			description: "index out of range",
+			values:      NewValues("a"),
+			indices:     []int{1},
+			expectedErr: true,
+		},
+	}
+	for _, test := range tests {
+		test := test // Capture range variable.
+		t.Run(test.description, func(t *testing.T) {
+			s, err := test.values.ScalarOf(test.indices...)
+			if test.expectedErr {
+				assert.Error(t, err, "Expected an error calculating scalar value")
+				return
+			}
+			require.NoError(t, err, "Error calculating scalar value")
+			assert.Equal(t, test.expected, s, "Expected and actual values are different")
+		})
+	}
+}